@@ -0,0 +1,102 @@
+package flagx
+
+import (
+	"strings"
+)
+
+// Positional returns the FlagSet declaring the positional arguments.
+// Every flag defined in it is a named positional argument, arguments are assigned in definition order
+// and converted with the same flag.Value as the flags, so all the definers like String, Duration,
+// IntSlice or Text can be used. Aliases are ignored.
+//
+// Arguments are optional unless marked with Required on the returned FlagSet.
+// The last argument of a slice or a set type is variadic and receives all the remaining arguments.
+// Parse prints the usage and returns an error if a required argument is missing,
+// a value is invalid or there are more arguments than declared.
+// Args, Arg and NArg still return the raw positional arguments.
+func (f *FlagSet) Positional() *FlagSet {
+	if f.positional == nil {
+		f.positional = NewFlagSet(f.fs.Name(), f.fs.Output())
+	}
+	return f.positional
+}
+
+// parsePositional sets the positional arguments from Args.
+func (f *FlagSet) parsePositional() error {
+	p := f.positional
+	if p == nil || len(p.order) == 0 {
+		return nil
+	}
+	args := f.Args()
+
+	for i, name := range p.order {
+		fl := p.fs.Lookup(name)
+		if p.isVariadic(name) {
+			if i < len(args) {
+				if err := fl.Value.(listValue).setList(args[i:]); err != nil {
+					return f.failf("invalid value %q for argument %s: %v", strings.Join(args[i:], " "), name, err)
+				}
+				p.preset[name] = true
+			}
+			args = nil
+			break
+		}
+		if i >= len(args) {
+			break
+		}
+		if err := p.fs.Set(name, args[i]); err != nil {
+			return f.failf("invalid value %q for argument %s: %v", args[i], name, err)
+		}
+	}
+
+	var missing []string
+	actual := p.actual()
+	for _, name := range p.order {
+		if p.required[name] && !actual[name] {
+			missing = append(missing, name)
+		}
+	}
+	switch {
+	case len(missing) == 1:
+		return f.failf("missing required argument: %s", missing[0])
+	case len(missing) > 1:
+		return f.failf("missing required arguments: %s", strings.Join(missing, ", "))
+	case len(args) > len(p.order):
+		return f.failf("too many arguments: %s", strings.Join(args[len(p.order):], " "))
+	}
+	return nil
+}
+
+// isVariadic reports whether the positional argument is the last one and has a slice or a set type.
+func (f *FlagSet) isVariadic(name string) bool {
+	if name != f.order[len(f.order)-1] {
+		return false
+	}
+	_, ok := f.fs.Lookup(name).Value.(listValue)
+	return ok
+}
+
+// positionalName returns the positional argument name as printed in usage,
+// like "src", "[dst]" or "files...".
+func (f *FlagSet) positionalName(name string) string {
+	display := name
+	if f.isVariadic(name) {
+		display += "..."
+	}
+	if !f.required[name] {
+		display = "[" + display + "]"
+	}
+	return display
+}
+
+// Synopsis returns the positional arguments as printed in usage, like "src [dst] [files...]".
+func (f *FlagSet) Synopsis() string {
+	if f.positional == nil {
+		return ""
+	}
+	names := make([]string, len(f.positional.order))
+	for i, name := range f.positional.order {
+		names[i] = f.positional.positionalName(name)
+	}
+	return strings.Join(names, " ")
+}
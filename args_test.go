@@ -0,0 +1,82 @@
+package flagx
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_Positional(t *testing.T) {
+	var verbose bool
+	var src, dst string
+	var timeout time.Duration
+	var files []string
+
+	fset := NewFlagSet("testing", io.Discard)
+	fset.Bool(&verbose, "verbose", "v", false, "verbose output")
+	args := fset.Positional()
+	args.String(&src, "src", "", "", "source")
+	args.String(&dst, "dst", "", "", "destination")
+	args.Duration(&timeout, "timeout", "", time.Second, "just a timeout")
+	args.StringSlice(&files, "files", "", nil, ",", "files to copy")
+	args.Required("src", "dst")
+
+	err := fset.Parse([]string{"-v", "a", "b", "5s", "x,y", "z"})
+	failIfErr(t, err)
+
+	mustEqual(t, verbose, true)
+	mustEqual(t, src, "a")
+	mustEqual(t, dst, "b")
+	mustEqual(t, timeout, 5*time.Second)
+	mustEqual(t, files, []string{"x,y", "z"})
+	mustEqual(t, fset.Args(), []string{"a", "b", "5s", "x,y", "z"})
+	mustEqual(t, fset.Synopsis(), "src dst [timeout] [files...]")
+}
+
+func TestFlagSet_PositionalBad(t *testing.T) {
+	newFlagSet := func() *FlagSet {
+		fset := NewFlagSet("testing", io.Discard)
+		args := fset.Positional()
+		args.String(new(string), "src", "", "", "source")
+		args.String(new(string), "dst", "", "", "destination")
+		args.Int(new(int), "count", "", 0, "just a count")
+		args.Required("src", "dst")
+		return fset
+	}
+
+	testCases := []struct {
+		args []string
+		err  string
+	}{
+		{nil, "missing required arguments: src, dst"},
+		{[]string{"a"}, "missing required argument: dst"},
+		{[]string{"a", "b", "c"}, `invalid value "c" for argument count: parse error`},
+		{[]string{"a", "b", "1", "d", "e"}, "too many arguments: d e"},
+	}
+
+	for _, tc := range testCases {
+		err := newFlagSet().Parse(tc.args)
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
+
+func TestFlagSet_PositionalPrintDefaults(t *testing.T) {
+	const usage = `  -verbose (-v)
+    	verbose output
+
+Arguments:
+  src string
+    	source (required)
+  ids... value
+    	just ids (default 1,2)
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.Positional().String(new(string), "src", "", "", "source")
+	fset.Positional().IntSlice(new([]int), "ids", "", []int{1, 2}, ",", "just ids")
+	fset.Positional().Required("src")
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+}
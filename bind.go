@@ -0,0 +1,213 @@
+package flagx
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Bind defines a flag for every exported field of the struct pointed to by v.
+// Fields are configured with the following tags:
+//
+//	flag    - flag name, "-" skips the field, by default the field name in kebab-case is used.
+//	alias   - flag alias, see Var.
+//	usage   - usage string.
+//	default - default value, by default the current field value is used.
+//	sep     - separator for slices, "," by default.
+//
+// Fields of nested structs are defined with the struct name as a prefix, like "db.timeout",
+// fields of embedded structs and pointers to structs are defined without a prefix unless the flag tag is set,
+// nil pointers are set to new structs.
+// Fields implementing flag.Value or encoding.TextUnmarshaler (via a pointer) are defined with Var and Text.
+// Bind returns an error if v is not a pointer to a struct or a field cannot be bound.
+func (f *FlagSet) Bind(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("flagx: bind value must be a non-nil pointer to a struct")
+	}
+	if err := f.bindStruct(rv.Elem(), ""); err != nil {
+		return fmt.Errorf("flagx: %w", err)
+	}
+	return nil
+}
+
+var (
+	valueType         = reflect.TypeOf((*flag.Value)(nil)).Elem()
+	textUnmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func (f *FlagSet) bindStruct(rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get("flag")
+		if name == "-" {
+			continue
+		}
+		if t := field.Type; field.Anonymous && name == "" {
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Struct && !isValue(t) {
+				// Flatten the embedded struct, like encoding/json does.
+				if err := f.bindEmbedded(rv.Field(i), prefix); err != nil {
+					return fmt.Errorf("field %s: %w", field.Name, err)
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		if name == "" {
+			name = kebabCase(field.Name)
+		}
+		name = prefix + name
+
+		if err := f.bindField(rv.Field(i), field, name); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// bindEmbedded defines the flags for the fields of the embedded struct or pointer to a struct,
+// a nil pointer is set to a new struct.
+func (f *FlagSet) bindEmbedded(fv reflect.Value, prefix string) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			if !fv.CanSet() {
+				return fmt.Errorf("cannot set nil pointer to unexported struct %s", fv.Type().Elem())
+			}
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+	return f.bindStruct(fv, prefix)
+}
+
+// isValue reports whether the type implements flag.Value or encoding.TextUnmarshaler via a pointer.
+func isValue(t reflect.Type) bool {
+	t = reflect.PtrTo(t)
+	return t.Implements(valueType) || t.Implements(textUnmarshalType)
+}
+
+func (f *FlagSet) bindField(fv reflect.Value, field reflect.StructField, name string) error {
+	alias, usage := field.Tag.Get("alias"), field.Tag.Get("usage")
+	sep := field.Tag.Get("sep")
+	if sep == "" {
+		sep = ","
+	}
+	ptr := fv.Addr().Interface()
+
+	switch {
+	case fv.Addr().Type().Implements(valueType):
+		f.Var(ptr.(flag.Value), name, alias, usage)
+	case fv.Addr().Type().Implements(textUnmarshalType):
+		f.Var(textValue{ptr.(encoding.TextUnmarshaler)}, name, alias, usage)
+	case fv.Kind() == reflect.Struct:
+		if _, ok := field.Tag.Lookup("default"); ok {
+			return errors.New("default is not supported for structs")
+		}
+		return f.bindStruct(fv, name+".")
+	default:
+		if !f.bindBasic(ptr, name, alias, sep, usage) {
+			return fmt.Errorf("unsupported type %s", fv.Type())
+		}
+	}
+
+	if def, ok := field.Tag.Lookup("default"); ok {
+		return f.setDefault(name, def)
+	}
+	return nil
+}
+
+// bindBasic defines a flag for the basic types, slices and sets, reports whether the type is supported.
+func (f *FlagSet) bindBasic(ptr interface{}, name, alias, sep, usage string) bool {
+	switch p := ptr.(type) {
+	case *bool:
+		f.Bool(p, name, alias, *p, usage)
+	case *int:
+		f.Int(p, name, alias, *p, usage)
+	case *int64:
+		f.Int64(p, name, alias, *p, usage)
+	case *uint:
+		f.Uint(p, name, alias, *p, usage)
+	case *uint64:
+		f.Uint64(p, name, alias, *p, usage)
+	case *string:
+		f.String(p, name, alias, *p, usage)
+	case *float64:
+		f.Float64(p, name, alias, *p, usage)
+	case *time.Duration:
+		f.Duration(p, name, alias, *p, usage)
+	case *[]bool:
+		f.BoolSlice(p, name, alias, *p, sep, usage)
+	case *[]int:
+		f.IntSlice(p, name, alias, *p, sep, usage)
+	case *[]int64:
+		f.Int64Slice(p, name, alias, *p, sep, usage)
+	case *[]uint:
+		f.UintSlice(p, name, alias, *p, sep, usage)
+	case *[]uint64:
+		f.Uint64Slice(p, name, alias, *p, sep, usage)
+	case *[]string:
+		f.StringSlice(p, name, alias, *p, sep, usage)
+	case *[]float64:
+		f.Float64Slice(p, name, alias, *p, sep, usage)
+	case *[]time.Duration:
+		f.DurationSlice(p, name, alias, *p, sep, usage)
+	case *map[int]struct{}:
+		f.IntSet(p, name, alias, *p, usage)
+	case *map[int64]struct{}:
+		f.Int64Set(p, name, alias, *p, usage)
+	case *map[uint]struct{}:
+		f.UintSet(p, name, alias, *p, usage)
+	case *map[uint64]struct{}:
+		f.Uint64Set(p, name, alias, *p, usage)
+	case *map[string]struct{}:
+		f.StringSet(p, name, alias, *p, usage)
+	case *map[float64]struct{}:
+		f.Float64Set(p, name, alias, *p, usage)
+	case *map[time.Duration]struct{}:
+		f.DurationSet(p, name, alias, *p, usage)
+	default:
+		return false
+	}
+	return true
+}
+
+// setDefault sets the flag to value and makes it the default value of the flag and its alias.
+func (f *FlagSet) setDefault(name, value string) error {
+	fl := f.fs.Lookup(name)
+	if err := fl.Value.Set(value); err != nil {
+		return fmt.Errorf("invalid default value %q for flag -%s: %w", value, name, err)
+	}
+	fl.DefValue = fl.Value.String()
+	if alias := f.aliases[name]; alias != "" {
+		f.fs.Lookup(alias).DefValue = fl.DefValue
+	}
+	return nil
+}
+
+// kebabCase converts a Go identifier like MaxConns or HTTPAddr to max-conns and http-addr.
+func kebabCase(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(rs[i-1]) || unicode.IsDigit(rs[i-1])
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if prevLower || (unicode.IsUpper(rs[i-1]) && nextLower) {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package flagx
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_Bind(t *testing.T) {
+	type DB struct {
+		Timeout time.Duration `default:"5s" usage:"db timeout"`
+		Hosts   []string      `sep:";"`
+	}
+	var cfg struct {
+		Addr     string `flag:"addr" alias:"a" default:":8080" usage:"listen address"`
+		MaxConns int    `usage:"max connections"`
+		Verbose  bool   `alias:"v"`
+		Ports    map[int]struct{}
+		IP       net.IP `default:"127.0.0.1"`
+		Skip     int    `flag:"-"`
+		DB       DB     `flag:"db"`
+		Delays   []time.Duration
+		internal int
+	}
+	cfg.MaxConns = 10
+
+	fset := NewFlagSet("testing", os.Stderr)
+	err := fset.Bind(&cfg)
+	failIfErr(t, err)
+
+	mustEqual(t, fset.Lookup("addr").DefValue, ":8080")
+	mustEqual(t, fset.Lookup("a").DefValue, ":8080")
+	mustEqual(t, fset.Lookup("max-conns").DefValue, "10")
+	mustEqual(t, fset.Lookup("db.timeout").Usage, "db timeout")
+	mustEqual(t, fset.Lookup("skip") == nil, true)
+	mustEqual(t, cfg.IP.String(), "127.0.0.1")
+
+	err = fset.Parse([]string{
+		"-a", ":9090", "-v", "-ports", "1,2", "-ip", "10.0.0.1",
+		"-db.hosts", "x;y", "-delays", "1s,2s",
+	})
+	failIfErr(t, err)
+
+	mustEqual(t, cfg.Addr, ":9090")
+	mustEqual(t, cfg.MaxConns, 10)
+	mustEqual(t, cfg.Verbose, true)
+	mustEqual(t, cfg.Ports, map[int]struct{}{1: {}, 2: {}})
+	mustEqual(t, cfg.IP.String(), "10.0.0.1")
+	mustEqual(t, cfg.DB.Timeout, 5*time.Second)
+	mustEqual(t, cfg.DB.Hosts, []string{"x", "y"})
+	mustEqual(t, cfg.Delays, []time.Duration{time.Second, 2 * time.Second})
+}
+
+func TestFlagSet_BindBad(t *testing.T) {
+	fset := NewFlagSet("testing", os.Stderr)
+	if err := fset.Bind(struct{}{}); err == nil {
+		t.Fatal("must fail")
+	}
+
+	var cfg1 struct {
+		Count int `default:"abc"`
+	}
+	if err := fset.Bind(&cfg1); err == nil {
+		t.Fatal("must fail")
+	}
+
+	var cfg2 struct {
+		Ch chan int
+	}
+	if err := fset.Bind(&cfg2); err == nil {
+		t.Fatal("must fail")
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	testCases := map[string]string{
+		"Addr":      "addr",
+		"MaxConns":  "max-conns",
+		"HTTPAddr":  "http-addr",
+		"UseHTTP2":  "use-http2",
+		"IP":        "ip",
+		"DBTimeout": "db-timeout",
+	}
+	for in, want := range testCases {
+		mustEqual(t, kebabCase(in), want)
+	}
+}
+
+type bindCommon struct {
+	Verbose bool `alias:"v"`
+}
+
+func TestFlagSet_BindEmbedded(t *testing.T) {
+	type Limits struct {
+		Retries int
+	}
+	var cfg struct {
+		bindCommon
+		Limits `flag:"limits"`
+		Addr   string
+	}
+	fset := NewFlagSet("testing", os.Stderr)
+	err := fset.Bind(&cfg)
+	failIfErr(t, err)
+
+	err = fset.Parse([]string{"-v", "-limits.retries", "3", "-addr", "x"})
+	failIfErr(t, err)
+	mustEqual(t, cfg.Verbose, true)
+	mustEqual(t, cfg.Retries, 3)
+	mustEqual(t, cfg.Addr, "x")
+}
+
+func TestFlagSet_BindEmbeddedPointer(t *testing.T) {
+	type Server struct {
+		Addr string
+	}
+	var cfg struct {
+		*bindCommon
+		*Server
+	}
+	cfg.bindCommon = &bindCommon{}
+	fset := NewFlagSet("testing", os.Stderr)
+	err := fset.Bind(&cfg)
+	failIfErr(t, err)
+
+	err = fset.Parse([]string{"-v", "-addr", "x"})
+	failIfErr(t, err)
+	mustEqual(t, cfg.Verbose, true)
+	mustEqual(t, cfg.Addr, "x")
+
+	var bad struct {
+		*bindCommon
+	}
+	err = NewFlagSet("testing", os.Stderr).Bind(&bad)
+	mustEqual(t, err.Error(), "flagx: field bindCommon: cannot set nil pointer to unexported struct flagx.bindCommon")
+}
+
+func TestFlagSet_BindNestedError(t *testing.T) {
+	var cfg struct {
+		DB struct {
+			Ch chan int
+		}
+	}
+	fset := NewFlagSet("testing", os.Stderr)
+	err := fset.Bind(&cfg)
+	mustEqual(t, err.Error(), "flagx: field DB: field Ch: unsupported type chan int")
+}
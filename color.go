@@ -0,0 +1,77 @@
+package flagx
+
+import (
+	"os"
+	"regexp"
+	"unicode/utf8"
+)
+
+// ColorMode defines when the usage and the parse errors are colored.
+type ColorMode int
+
+const (
+	// ColorNever never colors the output, the default.
+	ColorNever ColorMode = iota
+
+	// ColorAuto colors the output if it is a terminal, unless the NO_COLOR environment
+	// variable is not empty or TERM is dumb.
+	ColorAuto
+
+	// ColorAlways always colors the output.
+	ColorAlways
+)
+
+// styles are the ANSI escape codes of the styled parts of the output.
+var styles = map[string]string{
+	"flag":        "\x1b[1m",    // bold
+	"placeholder": "\x1b[4m",    // underline
+	"default":     "\x1b[2m",    // faint
+	"required":    "\x1b[31m",   // red
+	"deprecated":  "\x1b[33m",   // yellow
+	"heading":     "\x1b[1m",    // bold
+	"error":       "\x1b[1;31m", // bold red
+}
+
+const colorReset = "\x1b[0m"
+
+var escapeRegexp = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// SetColor sets when PrintDefaults, PrintUsage and the parse errors are colored with ANSI escape codes,
+// flag names, placeholders, default values, required and deprecated notes and headings are styled.
+// The output is never colored by default.
+func (f *FlagSet) SetColor(mode ColorMode) {
+	f.color = mode
+}
+
+// colors reports whether the output is colored.
+func (f *FlagSet) colors() bool {
+	switch f.color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	file, ok := f.fs.Output().(*os.File)
+	if !ok {
+		return false
+	}
+	_, ok = terminalWidth(file.Fd())
+	return ok
+}
+
+// colorize styles s, an empty s or an unknown style is returned as is.
+func colorize(style, s string) string {
+	code, ok := styles[style]
+	if !ok || s == "" {
+		return s
+	}
+	return code + s + colorReset
+}
+
+// textWidth returns the number of runes of s without the ANSI escape codes.
+func textWidth(s string) int {
+	return utf8.RuneCountInString(escapeRegexp.ReplaceAllString(s, ""))
+}
@@ -0,0 +1,68 @@
+package flagx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_SetColor(t *testing.T) {
+	const usage = "  \x1b[1m-timeout (-t)\x1b[0m \x1b[4mduration\x1b[0m\n" +
+		"    \tjust a timeout \x1b[2m(default 10s)\x1b[0m \x1b[31m(required)\x1b[0m\n" +
+		"\n\x1b[1mDebug:\x1b[0m\n" +
+		"  \x1b[1m-v\x1b[0m\tverbose output\n"
+
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Bool(new(bool), "v", "", false, "verbose output")
+	fset.Required("timeout")
+	fset.Group("Debug", "v")
+
+	fset.SetColor(ColorAlways)
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+
+	buf.Reset()
+	fset.SetColor(ColorAuto) // not a terminal.
+	fset.PrintDefaults()
+	mustEqual(t, bytes.Contains(buf.Bytes(), []byte("\x1b[")), false)
+}
+
+func TestFlagSet_ColorDefault(t *testing.T) {
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.Bool(new(bool), "v", "", false, "verbose output")
+	mustEqual(t, fset.color, ColorNever)
+
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), "  -v\tverbose output\n")
+}
+
+func TestFlagSet_SetColorErrors(t *testing.T) {
+	testCases := []struct {
+		mode ParseMode
+		err  string
+	}{
+		{StdlibMode, "\x1b[1;31minvalid value \"x\" for flag -t: parse error\x1b[0m\n"},
+		{GNUMode, "\x1b[1;31minvalid value \"x\" for flag -t: parse error\x1b[0m\n"},
+	}
+
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		fset := NewFlagSet("testing", &buf)
+		fset.SetMode(tc.mode)
+		fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+		fset.SetColor(ColorAlways)
+		fset.SetUsage(func() {})
+
+		err := fset.Parse([]string{"-t", "x"})
+		mustEqual(t, err != nil, true)
+		mustEqual(t, buf.String(), tc.err)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	mustEqual(t, textWidth(colorize("flag", "-timeout")), 8)
+	mustEqual(t, textWidth("héllo"), 5)
+}
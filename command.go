@@ -0,0 +1,207 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Command represents a command with its own flags, persistent flags and subcommands.
+// Persistent flags of a command are inherited by all its subcommands.
+type Command struct {
+	name       string
+	usage      string
+	output     io.Writer
+	run        func(args []string) error
+	flags      *FlagSet
+	persistent *FlagSet
+	parent     *Command
+	commands   []*Command
+}
+
+// NewCommand returns new Command with specified name, usage string and run function.
+// Run receives the arguments remaining after the flags are parsed, nil run means
+// the command only dispatches to its subcommands.
+func NewCommand(name, usage string, output io.Writer, run func(args []string) error) *Command {
+	c := &Command{
+		name:       name,
+		usage:      usage,
+		output:     output,
+		run:        run,
+		flags:      NewFlagSet(name, output),
+		persistent: NewFlagSet(name, output),
+	}
+	c.flags.SetUsage(c.PrintUsage)
+	return c
+}
+
+// Name returns the name of the command.
+func (c *Command) Name() string { return c.name }
+
+// Parent returns the parent of the command, nil for the root command.
+func (c *Command) Parent() *Command { return c.parent }
+
+// Flags returns the flags of the command.
+func (c *Command) Flags() *FlagSet { return c.flags }
+
+// PersistentFlags returns the flags of the command inherited by all the subcommands.
+func (c *Command) PersistentFlags() *FlagSet { return c.persistent }
+
+// AddCommand adds subcommands to the command.
+// AddCommand panics if a command with the same name is already added.
+func (c *Command) AddCommand(cmds ...*Command) {
+	for _, cmd := range cmds {
+		if c.lookup(cmd.name) != nil {
+			panic(fmt.Sprintf("flagx: command %s redefined", cmd.name))
+		}
+		cmd.parent = c
+		c.commands = append(c.commands, cmd)
+	}
+}
+
+// Execute parses the arguments, which should not include the command name,
+// and runs the command or dispatches to the subcommand named by the first positional argument.
+// Flag errors are printed with the usage and handled as set by SetErrorHandling on Flags.
+func (c *Command) Execute(args []string) error {
+	c.inheritFlags()
+
+	if err := c.flags.parse(args); err != nil {
+		return c.flags.handleError(err)
+	}
+	args = c.flags.Args()
+
+	if len(c.commands) > 0 && len(args) > 0 {
+		if cmd := c.lookup(args[0]); cmd != nil {
+			// Persistent flags are validated by the subcommand.
+			if err := c.flags.validate(c.isPersistent); err != nil {
+				return c.flags.handleError(c.flags.fail(err))
+			}
+			for name := range c.flags.actual() {
+				if c.isPersistent(name) {
+					cmd.flags.preset[name] = true
+					cmd.flags.inherited[name] = true
+					cmd.flags.sources[name] = c.flags.sources[name]
+				}
+			}
+			return cmd.Execute(args[1:])
+		}
+		if c.run == nil {
+			err := fmt.Errorf("unknown command %q for %s", args[0], c.path())
+			return c.flags.handleError(c.flags.fail(err))
+		}
+	}
+	if c.run == nil {
+		c.flags.usage()
+		return c.flags.handleError(flag.ErrHelp)
+	}
+	if err := c.flags.validate(nil); err != nil {
+		return c.flags.handleError(c.flags.fail(err))
+	}
+	return c.run(args)
+}
+
+// PrintUsage prints the usage of the command, its subcommands and flags to the command output.
+func (c *Command) PrintUsage() {
+	c.inheritFlags()
+
+	fmt.Fprintf(c.output, "Usage: %s [flags]", c.path())
+	switch synopsis := c.flags.Synopsis(); {
+	case len(c.commands) > 0:
+		fmt.Fprint(c.output, " <command> [args]\n")
+	case synopsis != "":
+		fmt.Fprintf(c.output, " %s\n", synopsis)
+	default:
+		fmt.Fprint(c.output, " [args]\n")
+	}
+	if c.usage != "" {
+		fmt.Fprintf(c.output, "\n%s\n", c.usage)
+	}
+
+	if len(c.commands) > 0 {
+		width := 0
+		for _, cmd := range c.commands {
+			if len(cmd.name) > width {
+				width = len(cmd.name)
+			}
+		}
+		fmt.Fprint(c.output, "\nCommands:\n")
+		for _, cmd := range c.commands {
+			fmt.Fprintf(c.output, "  %-*s  %s\n", width, cmd.name, cmd.usage)
+		}
+	}
+
+	fmt.Fprint(c.output, "\nFlags:\n")
+	c.flags.PrintDefaults()
+}
+
+// inheritFlags defines the persistent flags of the command and its ancestors in the command flags.
+func (c *Command) inheritFlags() {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		c.flags.addFlags(cmd.persistent)
+	}
+}
+
+// isPersistent reports whether the flag is a persistent flag of the command or its ancestors.
+func (c *Command) isPersistent(name string) bool {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if _, ok := cmd.persistent.aliases[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Command) lookup(name string) *Command {
+	for _, cmd := range c.commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// path returns the names of the command and its ancestors separated by a space.
+func (c *Command) path() string {
+	names := []string{c.name}
+	for cmd := c.parent; cmd != nil; cmd = cmd.parent {
+		names = append(names, cmd.name)
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, " ")
+}
+
+// addFlags defines the flags from other which are not yet defined in f, values are shared.
+func (f *FlagSet) addFlags(other *FlagSet) {
+	other.visitFlags(func(fl *flag.Flag) {
+		if f.Lookup(fl.Name) != nil {
+			return
+		}
+		alias := other.aliases[fl.Name]
+		f.Var(fl.Value, fl.Name, alias, fl.Usage)
+		f.fs.Lookup(fl.Name).DefValue = fl.DefValue
+		if alias != "" {
+			f.fs.Lookup(alias).DefValue = fl.DefValue
+		}
+		if key := other.EnvName(fl.Name); key != "" {
+			f.envs[fl.Name] = key
+		}
+		f.required[fl.Name] = other.required[fl.Name]
+		if title, ok := other.groupOf[fl.Name]; ok {
+			f.Group(title, fl.Name)
+		}
+		f.hidden[fl.Name] = other.hidden[fl.Name]
+	})
+	for name, d := range other.deprecated {
+		if _, ok := f.deprecated[name]; !ok {
+			f.deprecated[name] = d
+		}
+	}
+	for _, c := range other.constraints {
+		if !f.hasConstraint(c) {
+			f.constraints = append(f.constraints, c)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package flagx
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	var buf bytes.Buffer
+	var verbose bool
+	var force bool
+	var gotArgs []string
+
+	root := NewCommand("app", "just an app", &buf, nil)
+	root.PersistentFlags().Bool(&verbose, "verbose", "v", false, "verbose output")
+
+	remote := NewCommand("remote", "manage remotes", &buf, nil)
+	add := NewCommand("add", "add a remote", &buf, func(args []string) error {
+		gotArgs = args
+		return nil
+	})
+	add.Flags().Bool(&force, "force", "f", false, "force add")
+	remote.AddCommand(add)
+	root.AddCommand(remote)
+
+	err := root.Execute([]string{"remote", "add", "-v", "-f", "origin", "url"})
+	failIfErr(t, err)
+
+	mustEqual(t, verbose, true)
+	mustEqual(t, force, true)
+	mustEqual(t, gotArgs, []string{"origin", "url"})
+}
+
+func TestCommand_PersistentBeforeSubcommand(t *testing.T) {
+	var buf bytes.Buffer
+	var verbose bool
+	var called bool
+
+	root := NewCommand("app", "", &buf, nil)
+	root.PersistentFlags().Bool(&verbose, "verbose", "v", false, "verbose output")
+	root.AddCommand(NewCommand("run", "", &buf, func(args []string) error {
+		called = true
+		return nil
+	}))
+
+	err := root.Execute([]string{"-v", "run"})
+	failIfErr(t, err)
+	mustEqual(t, verbose, true)
+	mustEqual(t, called, true)
+}
+
+func TestCommand_PersistentOverridesEnv(t *testing.T) {
+	t.Setenv("APP_REGION", "env")
+
+	var buf bytes.Buffer
+	var region string
+
+	root := NewCommand("app", "", &buf, nil)
+	root.PersistentFlags().String(&region, "region", "r", "", "just a region")
+	root.PersistentFlags().Env("region", "APP_REGION")
+	root.AddCommand(NewCommand("run", "", &buf, func(args []string) error { return nil }))
+
+	err := root.Execute([]string{"-region", "cli", "run"})
+	failIfErr(t, err)
+	mustEqual(t, region, "cli")
+}
+
+func TestCommand_PersistentDeprecated(t *testing.T) {
+	var buf bytes.Buffer
+	var addr string
+
+	root := NewCommand("app", "", &buf, nil)
+	root.PersistentFlags().String(new(string), "old", "", "", "just an old flag")
+	root.PersistentFlags().String(&addr, "new", "", "", "just a new flag")
+	root.PersistentFlags().Deprecated("old", "new", true)
+	root.AddCommand(NewCommand("run", "", &buf, func(args []string) error { return nil }))
+
+	err := root.Execute([]string{"-old", "x", "run"})
+	failIfErr(t, err)
+	mustEqual(t, addr, "x")
+	mustEqual(t, buf.String(), "warning: flag -old is deprecated, use -new\n")
+}
+
+func TestCommand_Usage(t *testing.T) {
+	const usage = `Usage: app [flags] <command> [args]
+
+just an app
+
+Commands:
+  remote  manage remotes
+  status  show status
+
+Flags:
+  -verbose (-v)
+    	verbose output
+`
+	var buf bytes.Buffer
+	root := NewCommand("app", "just an app", &buf, nil)
+	root.PersistentFlags().Bool(new(bool), "verbose", "v", false, "verbose output")
+	root.AddCommand(
+		NewCommand("remote", "manage remotes", &buf, nil),
+		NewCommand("status", "show status", &buf, nil),
+	)
+
+	err := root.Execute(nil)
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatal(err)
+	}
+	mustEqual(t, buf.String(), usage)
+}
+
+func TestCommand_Unknown(t *testing.T) {
+	var buf bytes.Buffer
+	root := NewCommand("app", "", &buf, nil)
+	root.AddCommand(NewCommand("run", "", &buf, nil))
+
+	err := root.Execute([]string{"walk"})
+	mustEqual(t, err.Error(), `unknown command "walk" for app`)
+	mustEqual(t, strings.HasPrefix(buf.String(), "unknown command \"walk\" for app\nUsage: app [flags]"), true)
+
+	root.Flags().SetErrorHandling(flag.PanicOnError)
+	defer func() {
+		mustEqual(t, recover() != nil, true)
+	}()
+	_ = root.Execute([]string{"walk"})
+	t.Fatal("must panic")
+}
+
+func TestCommand_HelpPanicOnError(t *testing.T) {
+	var buf bytes.Buffer
+	root := NewCommand("app", "", &buf, nil)
+	root.Flags().SetErrorHandling(flag.PanicOnError)
+
+	defer func() {
+		mustEqual(t, recover(), flag.ErrHelp)
+		mustEqual(t, strings.HasPrefix(buf.String(), "Usage: app [flags]"), true)
+	}()
+	_ = root.Execute(nil)
+	t.Fatal("must panic")
+}
+
+func TestCommand_ValidationError(t *testing.T) {
+	var buf bytes.Buffer
+	run := NewCommand("run", "run the app", &buf, func(args []string) error { return nil })
+	run.Flags().String(new(string), "region", "r", "", "just a region")
+	run.Flags().Required("region")
+	root := NewCommand("app", "", &buf, nil)
+	root.AddCommand(run)
+
+	err := root.Execute([]string{"run"})
+	mustEqual(t, err.Error(), "missing required flag: -region (-r)")
+	mustEqual(t, strings.HasPrefix(buf.String(), "missing required flag: -region (-r)\nUsage: app run [flags]"), true)
+
+	run.Flags().SetErrorHandling(flag.PanicOnError)
+	defer func() {
+		mustEqual(t, recover() != nil, true)
+	}()
+	_ = root.Execute([]string{"run"})
+	t.Fatal("must panic")
+}
@@ -0,0 +1,373 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// completionOutput is where the completion script requested on the command line is written.
+var completionOutput io.Writer = os.Stdout
+
+// CompleteFiles sets the completion of the flag with the specified name or alias to file names,
+// pattern like "*.json" limits the files, empty pattern means any file.
+// CompleteFiles panics if the flag is not defined.
+func (f *FlagSet) CompleteFiles(name, pattern string) {
+	f.completions[f.mustCanonical(name)] = completion{kind: completeFiles, pattern: pattern}
+}
+
+// CompleteDirs sets the completion of the flag with the specified name or alias to directory names.
+// CompleteDirs panics if the flag is not defined.
+func (f *FlagSet) CompleteDirs(name string) {
+	f.completions[f.mustCanonical(name)] = completion{kind: completeDirs}
+}
+
+// Complete sets the completion of the flag with the specified name or alias to the values returned by fn,
+// fn receives the word being completed, like "eu-" for "--region eu-<TAB>", and may return values
+// not matching it, they are filtered by the shell. Complete works for the positional arguments too,
+// see Positional. The generated scripts call the program back, so EnableCompletion must be called.
+// Complete panics if the flag is not defined.
+func (f *FlagSet) Complete(name string, fn func(prefix string) []string) {
+	f.completions[f.mustCanonical(name)] = completion{kind: completeFunc, fn: fn}
+}
+
+// EnableCompletion defines a hidden flag with the specified name, like "completion".
+// When the flag is set, Parse writes the completion script for the shell given as
+// the flag value to stdout and returns flag.ErrHelp without setting the other sources
+// or validating the flags.
+// Like: "tool -completion bash > /etc/bash_completion.d/tool".
+//
+// It also enables the hidden "__complete" argument used by the scripts to call back
+// the program for dynamic values, see Complete.
+// EnableCompletion panics if a flag with the name is already defined.
+func (f *FlagSet) EnableCompletion(name string) {
+	if _, ok := f.canonical(name); ok {
+		panic(fmt.Sprintf("flagx: flag %s redefined", name))
+	}
+	f.completionFlag = name
+	// The flag is not in order, so the usage, the docs and the completion scripts skip it.
+	f.fs.StringVar(&f.completionShell, name, "", "write the completion script for the `shell`: bash, zsh or fish")
+}
+
+// Completion writes the completion script for the shell, one of "bash", "zsh" or "fish",
+// the FlagSet name is used as the program name.
+func (f *FlagSet) Completion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		f.bashCompletion(w)
+	case "zsh":
+		f.zshCompletion(w)
+	case "fish":
+		f.fishCompletion(w)
+	default:
+		return fmt.Errorf("flagx: unsupported shell %q", shell)
+	}
+	return nil
+}
+
+type completionKind int
+
+const (
+	completeNone completionKind = iota
+	completeFiles
+	completeDirs
+	completeFunc
+)
+
+// completeCommand is the first argument of the hidden protocol used by the completion scripts,
+// the other arguments are the words typed after the program name, the last one is being completed.
+const completeCommand = "__complete"
+
+type completion struct {
+	kind    completionKind
+	pattern string
+	fn      func(prefix string) []string
+}
+
+// writeCompletion writes the completion script requested on the command line.
+func (f *FlagSet) writeCompletion(shell string) error {
+	if err := f.Completion(completionOutput, shell); err != nil {
+		fmt.Fprintln(f.fs.Output(), err)
+		return err
+	}
+	return flag.ErrHelp
+}
+
+// completionFlag describes a flag for the completion scripts.
+type completionFlag struct {
+	names      []string // name and alias with the dashes.
+	usage      string   // first line of the usage.
+	valueName  string   // the value placeholder, see flag.UnquoteUsage.
+	isBool     bool
+	completion completion
+}
+
+func (f *FlagSet) completionFlags() []completionFlag {
+	var flags []completionFlag
+	f.visitFlags(func(fl *flag.Flag) {
+		if f.hidden[fl.Name] {
+			return
+		}
+		cf := completionFlag{
+			names:      []string{f.dash(fl.Name) + fl.Name},
+			usage:      fl.Usage,
+			isBool:     isBoolFlag(fl.Value),
+			completion: f.completions[fl.Name],
+		}
+		if alias := f.aliases[fl.Name]; alias != "" {
+			cf.names = append(cf.names, f.dash(alias)+alias)
+		}
+		if i := strings.IndexByte(cf.usage, '\n'); i >= 0 {
+			cf.usage = cf.usage[:i]
+		}
+		cf.valueName, cf.usage = flag.UnquoteUsage(&flag.Flag{Usage: cf.usage, Value: fl.Value})
+		flags = append(flags, cf)
+	})
+	return flags
+}
+
+// programName returns the FlagSet name and its form usable as a shell identifier.
+func (f *FlagSet) programName() (string, string) {
+	name := f.fs.Name()
+	ident := strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	return name, ident
+}
+
+// completeWords returns the candidates for the last of the words, the words are the arguments typed so far.
+func (f *FlagSet) completeWords(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur, prev := words[len(words)-1], words[:len(words)-1]
+
+	var positional int
+	terminated := false // the words after "--" are positional arguments.
+	for i := 0; i < len(prev); i++ {
+		word := prev[i]
+		if word == "--" && !terminated {
+			terminated = true
+			continue
+		}
+		if terminated || word == "-" || !strings.HasPrefix(word, "-") {
+			positional++
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(word, "-"), "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		fl := f.fs.Lookup(name)
+		if fl == nil || isBoolFlag(fl.Value) {
+			continue
+		}
+		if i == len(prev)-1 {
+			return f.completeValue(f, name, cur)
+		}
+		i++ // skip the flag value.
+	}
+
+	if !terminated && strings.HasPrefix(cur, "-") {
+		var names []string
+		for _, fl := range f.completionFlags() {
+			for _, name := range fl.names {
+				if strings.HasPrefix(name, cur) {
+					names = append(names, name)
+				}
+			}
+		}
+		return names
+	}
+
+	p := f.positional
+	if p == nil || len(p.order) == 0 {
+		return nil
+	}
+	if positional >= len(p.order) {
+		name := p.order[len(p.order)-1]
+		if !p.isVariadic(name) {
+			return nil
+		}
+		positional = len(p.order) - 1
+	}
+	return f.completeValue(p, p.order[positional], cur)
+}
+
+// completeValue returns the values of the flag from fset for the prefix.
+func (f *FlagSet) completeValue(fset *FlagSet, name, prefix string) []string {
+	name, _ = fset.canonical(name)
+	c := fset.completions[name]
+	if c.kind != completeFunc {
+		return nil
+	}
+	return c.fn(prefix)
+}
+
+// hasDynamicPositional reports whether any positional argument has a completion function.
+func (f *FlagSet) hasDynamicPositional() bool {
+	if f.positional == nil {
+		return false
+	}
+	for _, c := range f.positional.completions {
+		if c.kind == completeFunc {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FlagSet) bashCompletion(w io.Writer) {
+	name, ident := f.programName()
+	flags := f.completionFlags()
+
+	var all []string
+	for _, fl := range flags {
+		all = append(all, fl.names...)
+	}
+
+	fmt.Fprintf(w, "# bash completion for %s\n", name)
+	fmt.Fprintf(w, "_%s_complete() {\n", ident)
+	fmt.Fprint(w, "\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprint(w, "\tcase \"$prev\" in\n")
+	for _, fl := range flags {
+		if fl.isBool {
+			continue
+		}
+		fmt.Fprintf(w, "\t%s)\n", strings.Join(fl.names, "|"))
+		switch fl.completion.kind {
+		case completeFiles:
+			if fl.completion.pattern != "" {
+				fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -f -X '!%s' -o plusdirs -- \"$cur\"))\n", fl.completion.pattern)
+			} else {
+				fmt.Fprint(w, "\t\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n")
+			}
+		case completeDirs:
+			fmt.Fprint(w, "\t\tCOMPREPLY=($(compgen -d -- \"$cur\"))\n")
+		case completeFunc:
+			fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W \"$(%s)\" -- \"$cur\"))\n", bashCallback(name))
+		default:
+			fmt.Fprint(w, "\t\tCOMPREPLY=()\n")
+		}
+		fmt.Fprint(w, "\t\treturn\n\t\t;;\n")
+	}
+	fmt.Fprint(w, "\tesac\n")
+	fmt.Fprint(w, "\tif [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(all, " ")))
+	fmt.Fprint(w, "\t\treturn\n\tfi\n")
+	if f.hasDynamicPositional() {
+		fmt.Fprintf(w, "\tCOMPREPLY=($(compgen -W \"$(%s)\" -- \"$cur\"))\n", bashCallback(name))
+	} else {
+		fmt.Fprint(w, "\tCOMPREPLY=($(compgen -f -- \"$cur\"))\n")
+	}
+	fmt.Fprint(w, "}\n")
+	fmt.Fprintf(w, "complete -F _%s_complete %s\n", ident, name)
+}
+
+func (f *FlagSet) zshCompletion(w io.Writer) {
+	name, _ := f.programName()
+
+	fmt.Fprintf(w, "#compdef %s\n\n", name)
+	fmt.Fprint(w, "_arguments \\\n")
+	for _, fl := range f.completionFlags() {
+		spec := fl.names[0]
+		if len(fl.names) > 1 {
+			spec = "(" + strings.Join(fl.names, " ") + ")'{" + strings.Join(fl.names, ",") + "}'"
+		}
+		spec += "[" + zshEscape(fl.usage) + "]"
+		if !fl.isBool {
+			switch fl.completion.kind {
+			case completeFiles:
+				if fl.completion.pattern != "" {
+					spec += ":file:_files -g \"" + zshEscape(fl.completion.pattern) + "\""
+				} else {
+					spec += ":file:_files"
+				}
+			case completeDirs:
+				spec += ":dir:_files -/"
+			case completeFunc:
+				spec += ":" + zshEscape(fl.valueName) + ":" + zshCallback(name)
+			default:
+				spec += ":" + zshEscape(fl.valueName) + ": "
+			}
+		}
+		fmt.Fprintf(w, "\t'%s' \\\n", spec)
+	}
+	if f.hasDynamicPositional() {
+		fmt.Fprintf(w, "\t'*:arg:%s'\n", zshCallback(name))
+	} else {
+		fmt.Fprint(w, "\t'*:file:_files'\n")
+	}
+}
+
+func (f *FlagSet) fishCompletion(w io.Writer) {
+	name, _ := f.programName()
+
+	fmt.Fprintf(w, "# fish completion for %s\n", name)
+	for _, fl := range f.completionFlags() {
+		var b strings.Builder
+		fmt.Fprintf(&b, "complete -c %s", name)
+		for _, n := range fl.names {
+			switch {
+			case strings.HasPrefix(n, "--"):
+				fmt.Fprintf(&b, " -l %s", n[2:])
+			case f.mode == GNUMode:
+				fmt.Fprintf(&b, " -s %s", n[1:])
+			default:
+				fmt.Fprintf(&b, " -o %s", n[1:])
+			}
+		}
+		if !fl.isBool {
+			switch fl.completion.kind {
+			case completeFiles:
+				b.WriteString(" -r -F")
+			case completeDirs:
+				b.WriteString(" -x -a '(__fish_complete_directories)'")
+			case completeFunc:
+				fmt.Fprintf(&b, " -x -a '(%s)'", fishCallback(name))
+			default:
+				b.WriteString(" -x")
+			}
+		}
+		if fl.usage != "" {
+			fmt.Fprintf(&b, " -d %s", shellQuote(fl.usage))
+		}
+		fmt.Fprintln(w, b.String())
+	}
+	if f.hasDynamicPositional() {
+		fmt.Fprintf(w, "complete -c %s -f -a '(%s)'\n", name, fishCallback(name))
+	}
+}
+
+// bashCallback returns the command calling the program back for the completion values.
+func bashCallback(name string) string {
+	return name + " " + completeCommand + ` "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null`
+}
+
+// zshCallback returns the _arguments action calling the program back for the completion values.
+func zshCallback(name string) string {
+	return `{compadd -- ${(f)"$(` + name + " " + completeCommand + ` "${(@)words[2,CURRENT]}" 2>/dev/null)"}}`
+}
+
+// fishCallback returns the command calling the program back for the completion values.
+func fishCallback(name string) string {
+	return name + " " + completeCommand + " (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null"
+}
+
+// shellQuote quotes s with single quotes.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// zshEscape escapes s to be used inside a single-quoted _arguments spec.
+func zshEscape(s string) string {
+	s = strings.ReplaceAll(s, "'", `'\''`)
+	s = strings.ReplaceAll(s, "[", `\[`)
+	s = strings.ReplaceAll(s, "]", `\]`)
+	return strings.ReplaceAll(s, ":", `\:`)
+}
@@ -0,0 +1,202 @@
+package flagx
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_CompletionBash(t *testing.T) {
+	const script = `# bash completion for my-app
+_my_app_complete() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+	case "$prev" in
+	-config|-c)
+		COMPREPLY=($(compgen -f -X '!*.json' -o plusdirs -- "$cur"))
+		return
+		;;
+	-dir)
+		COMPREPLY=($(compgen -d -- "$cur"))
+		return
+		;;
+	-timeout|-t)
+		COMPREPLY=()
+		return
+		;;
+	esac
+	if [[ "$cur" == -* ]]; then
+		COMPREPLY=($(compgen -W '-config -c -dir -timeout -t -verbose -v' -- "$cur"))
+		return
+	fi
+	COMPREPLY=($(compgen -f -- "$cur"))
+}
+complete -F _my_app_complete my-app
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.String(new(string), "config", "c", "", "config file")
+	fset.String(new(string), "dir", "", "", "work dir")
+	fset.CompleteFiles("config", "*.json")
+	fset.CompleteDirs("dir")
+
+	err := fset.Completion(&buf, "bash")
+	failIfErr(t, err)
+	mustEqual(t, buf.String(), script)
+}
+
+func TestFlagSet_CompletionZshFish(t *testing.T) {
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.String(new(string), "config", "c", "", "config file")
+	fset.String(new(string), "dir", "", "", "work dir")
+	fset.CompleteFiles("config", "*.json")
+	fset.CompleteDirs("dir")
+
+	var buf bytes.Buffer
+	err := fset.Completion(&buf, "zsh")
+	failIfErr(t, err)
+	for _, want := range []string{
+		"#compdef my-app\n",
+		`'(-config -c)'{-config,-c}'[config file]:file:_files -g "*.json"' \`,
+		`'-dir[work dir]:dir:_files -/' \`,
+		`'(-timeout -t)'{-timeout,-t}'[just a timeout]:duration: ' \`,
+		`'(-verbose -v)'{-verbose,-v}'[verbose output]' \`,
+	} {
+		mustEqual(t, strings.Contains(buf.String(), want), true)
+	}
+
+	buf.Reset()
+	fset.SetMode(GNUMode)
+	err = fset.Completion(&buf, "fish")
+	failIfErr(t, err)
+	for _, want := range []string{
+		"complete -c my-app -l config -s c -r -F -d 'config file'\n",
+		"complete -c my-app -l dir -x -a '(__fish_complete_directories)' -d 'work dir'\n",
+		"complete -c my-app -l timeout -s t -x -d 'just a timeout'\n",
+		"complete -c my-app -l verbose -s v -d 'verbose output'\n",
+	} {
+		mustEqual(t, strings.Contains(buf.String(), want), true)
+	}
+
+	if err := fset.Completion(&buf, "cmd"); err == nil {
+		t.Fatal("must fail")
+	}
+}
+
+func TestFlagSet_EnableCompletion(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(w io.Writer) { completionOutput = w }(completionOutput)
+	completionOutput = &buf
+
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.EnableCompletion("completion")
+
+	err := fset.Parse([]string{"-completion", "bash"})
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatal(err)
+	}
+	mustEqual(t, strings.HasPrefix(buf.String(), "# bash completion for my-app\n"), true)
+
+	buf.Reset()
+	err = fset.Parse([]string{"--completion=fish"})
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatal(err)
+	}
+	mustEqual(t, strings.HasPrefix(buf.String(), "# fish completion for my-app\n"), true)
+
+	buf.Reset()
+	err = fset.Parse([]string{"-v", "-completion", "zsh"})
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatal(err)
+	}
+	mustEqual(t, strings.HasPrefix(buf.String(), "#compdef my-app\n"), true)
+
+	err = fset.Parse([]string{"-completion"})
+	mustEqual(t, err.Error(), "flag needs an argument: -completion")
+
+	var usage bytes.Buffer
+	fset.fs.SetOutput(&usage)
+	fset.PrintDefaults()
+	mustEqual(t, strings.Contains(usage.String(), "completion"), false)
+}
+
+func TestFlagSet_CompleteFunc(t *testing.T) {
+	regions := func(prefix string) []string {
+		return []string{"eu-west", "eu-north", "us-east"}
+	}
+	clusters := func(prefix string) []string {
+		return []string{"prod", "staging"}
+	}
+
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.String(new(string), "config", "c", "", "config file")
+	fset.String(new(string), "dir", "", "", "work dir")
+	fset.CompleteFiles("config", "*.json")
+	fset.CompleteDirs("dir")
+	fset.String(new(string), "region", "r", "", "cloud region")
+	fset.Complete("region", regions)
+	fset.Positional().String(new(string), "cluster", "", "", "cluster name")
+	fset.Positional().StringSlice(new([]string), "files", "", nil, ",", "files")
+	fset.Positional().Complete("cluster", clusters)
+
+	testCases := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"--region", "eu"}, []string{"eu-west", "eu-north", "us-east"}},
+		{[]string{"-v", "-r", ""}, []string{"eu-west", "eu-north", "us-east"}},
+		{[]string{"-c", "x.json", "-t"}, []string{"-timeout", "-t"}},
+		{[]string{"-v", "-t", "1s", "pr"}, []string{"prod", "staging"}},
+		{[]string{"prod", ""}, nil},
+		{[]string{"-config", ""}, nil},
+		{nil, []string{"prod", "staging"}},
+		{[]string{"--", ""}, []string{"prod", "staging"}},
+		{[]string{"-v", "--", "-p"}, []string{"prod", "staging"}},
+		{[]string{"--", "-r", ""}, nil},
+	}
+	for _, tc := range testCases {
+		mustEqual(t, fset.completeWords(tc.words), tc.want)
+	}
+
+	var buf bytes.Buffer
+	err := fset.Completion(&buf, "bash")
+	failIfErr(t, err)
+	for _, want := range []string{
+		"\t-region|-r)\n\t\tCOMPREPLY=($(compgen -W \"$(my-app __complete \"${COMP_WORDS[@]:1:$COMP_CWORD}\" 2>/dev/null)\" -- \"$cur\"))\n",
+		"\tCOMPREPLY=($(compgen -W \"$(my-app __complete \"${COMP_WORDS[@]:1:$COMP_CWORD}\" 2>/dev/null)\" -- \"$cur\"))\n}\n",
+	} {
+		mustEqual(t, strings.Contains(buf.String(), want), true)
+	}
+
+	buf.Reset()
+	err = fset.Completion(&buf, "fish")
+	failIfErr(t, err)
+	mustEqual(t, strings.Contains(buf.String(), "complete -c my-app -f -a '(my-app __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'\n"), true)
+}
+
+func TestFlagSet_CompleteCallback(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(w io.Writer) { completionOutput = w }(completionOutput)
+	completionOutput = &buf
+
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.String(new(string), "region", "r", "", "cloud region")
+	fset.Complete("r", func(prefix string) []string { return []string{"eu", "us"} })
+	fset.EnableCompletion("completion")
+
+	err := fset.Parse([]string{"__complete", "-r", ""})
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatal(err)
+	}
+	mustEqual(t, buf.String(), "eu\nus\n")
+}
@@ -0,0 +1,236 @@
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UnknownKeys defines how the configuration loaders handle the keys that match no flag.
+type UnknownKeys int
+
+const (
+	// UnknownKeysError makes the loaders return an error.
+	UnknownKeysError UnknownKeys = iota
+
+	// UnknownKeysWarn makes the loaders print a warning and ignore the key.
+	UnknownKeysWarn
+)
+
+// SetUnknownKeys sets how the configuration loaders like LoadJSON handle the keys that match no flag.
+// The default is UnknownKeysError.
+func (f *FlagSet) SetUnknownKeys(unknownKeys UnknownKeys) {
+	f.unknownKeys = unknownKeys
+}
+
+// LoadConfigFile loads flag values from the file at path, like LoadJSON.
+// The format is chosen by the extension: .json, .toml, .yaml or .yml, .ini and .env,
+// files named .env or starting with ".env." are dotenv files too.
+// The path is reported in the errors and by Source.
+func (f *FlagSet) LoadConfigFile(path string) error {
+	var parse func([]byte) ([]configValue, error)
+	switch base := filepath.Base(path); {
+	case base == ".env" || strings.HasPrefix(base, ".env."):
+		parse = parseDotenv
+	default:
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".json":
+			parse = parseJSON
+		case ".toml":
+			parse = parseTOML
+		case ".yaml", ".yml":
+			parse = parseYAML
+		case ".ini":
+			parse = parseINI
+		case ".env":
+			parse = parseDotenv
+		default:
+			return fmt.Errorf("unsupported config file extension %q", ext)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return f.loadConfig(file, path, parse)
+}
+
+// loadConfig reads and parses the configuration file from r and stores its values,
+// file is the name of the file used in the messages and by Source, empty if unknown.
+func (f *FlagSet) loadConfig(r io.Reader, file string, parse func([]byte) ([]configValue, error)) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	values, err := parse(src)
+	if err != nil {
+		if file != "" {
+			err = fmt.Errorf("%s: %w", file, err)
+		}
+		return err
+	}
+	for i := range values {
+		values[i].file = file
+	}
+	return f.addConfig(values)
+}
+
+// configValue is a flag value loaded from a configuration file.
+type configValue struct {
+	key    string // the key as written in the file, a dotted name for the nested keys.
+	name   string // the flag name, set by addConfig.
+	values []string
+	list   bool   // whether values is an array, otherwise it has a single value.
+	env    bool   // whether key is an environment variable name, see LoadDotenv.
+	file   string // the name of the file, empty if unknown.
+	line   int    // the line in the file, 0 if unknown.
+}
+
+// where returns the key with its file and line, if known, for the messages.
+func (c configValue) where() string {
+	switch {
+	case c.file != "" && c.line > 0:
+		return fmt.Sprintf("%s at %s:%d", c.key, c.file, c.line)
+	case c.file != "":
+		return fmt.Sprintf("%s in %s", c.key, c.file)
+	case c.line > 0:
+		return fmt.Sprintf("%s at line %d", c.key, c.line)
+	}
+	return c.key
+}
+
+// addConfig resolves the keys of the values to the flags and stores the values to be applied by Parse,
+// a value replaces the values of the same flag loaded before. Nothing is stored if an error is returned.
+func (f *FlagSet) addConfig(values []configValue) error {
+	var resolved []configValue
+	for _, v := range values {
+		name, ok := f.canonical(v.key)
+		if v.env {
+			name, ok = f.envFlag(v.key)
+		}
+		if !ok {
+			if f.unknownKeys == UnknownKeysWarn {
+				fmt.Fprintf(f.fs.Output(), "warning: unknown config key %s\n", v.where())
+				continue
+			}
+			return fmt.Errorf("unknown config key %s", v.where())
+		}
+		if _, ok := f.fs.Lookup(name).Value.(listValue); v.list && !ok {
+			return fmt.Errorf("config key %s: flag %s%s does not accept a list", v.where(), f.dash(name), name)
+		}
+		v.name = name
+		resolved = append(resolved, v)
+	}
+
+	for _, v := range resolved {
+		for i, c := range f.config {
+			if c.name == v.name {
+				f.config = append(f.config[:i], f.config[i+1:]...)
+				break
+			}
+		}
+		f.config = append(f.config, v)
+	}
+	return nil
+}
+
+// parseConfig sets the flags that were not set on the command line or from the environment
+// from the loaded configuration files.
+func (f *FlagSet) parseConfig() error {
+	if len(f.config) == 0 {
+		return nil
+	}
+	actual := f.actual()
+	for _, c := range f.config {
+		if actual[c.name] {
+			continue
+		}
+		if !c.list {
+			if err := f.fs.Set(c.name, c.values[0]); err != nil {
+				return fmt.Errorf("invalid value %q for config key %s: %w", c.values[0], c.where(), err)
+			}
+			f.sources[c.name] = Source{Kind: SourceConfig, Name: c.file, Line: c.line}
+			continue
+		}
+		if err := f.fs.Lookup(c.name).Value.(listValue).setList(c.values); err != nil {
+			return fmt.Errorf("invalid value %q for config key %s: %w", strings.Join(c.values, " "), c.where(), err)
+		}
+		// setList does not mark the flag as set.
+		f.preset[c.name] = true
+		f.sources[c.name] = Source{Kind: SourceConfig, Name: c.file, Line: c.line}
+	}
+	return nil
+}
+
+// SourceKind defines where the value of a flag comes from.
+type SourceKind int
+
+const (
+	// SourceDefault is the default value of the flag.
+	SourceDefault SourceKind = iota
+
+	// SourceFlag is the command line.
+	SourceFlag
+
+	// SourceEnv is an environment variable, see UseEnv.
+	SourceEnv
+
+	// SourceConfig is a configuration file, see LoadConfigFile.
+	SourceConfig
+)
+
+// Source describes where the value of a flag comes from, see FlagSet.Source.
+type Source struct {
+	Kind SourceKind
+	Name string // the environment variable for SourceEnv, the file for SourceConfig, empty if unknown.
+	Line int    // the line in the file for SourceConfig, 0 if unknown.
+}
+
+// String returns the source like "command line", "env APP_PORT" or "config.yaml:3".
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceFlag:
+		return "command line"
+	case SourceEnv:
+		return "env " + s.Name
+	case SourceConfig:
+		name := s.Name
+		if name == "" {
+			name = "config"
+		}
+		if s.Line > 0 {
+			return fmt.Sprintf("%s:%d", name, s.Line)
+		}
+		return name
+	default:
+		return "default"
+	}
+}
+
+// precedence returns the rank of the source, a value from a source with a higher rank is kept.
+func (s Source) precedence() int {
+	switch s.Kind {
+	case SourceFlag:
+		return 3
+	case SourceEnv:
+		return 2
+	case SourceConfig:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Source returns where the value of the flag with the specified name or alias comes from after Parse.
+// SourceDefault is returned if the flag is not set or not defined.
+func (f *FlagSet) Source(name string) Source {
+	name, ok := f.canonical(name)
+	if !ok {
+		return Source{}
+	}
+	return f.sources[name]
+}
@@ -0,0 +1,172 @@
+package flagx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_ConfigPrecedence(t *testing.T) {
+	t.Setenv("TESTING_TIMEOUT", "20s")
+
+	var d time.Duration
+	var name, addr string
+	var count int
+	fset := NewFlagSet("testing", io.Discard)
+	fset.Duration(&d, "timeout", "t", 10*time.Second, "just a timeout")
+	fset.String(&name, "name", "n", "def", "just a name")
+	fset.String(&addr, "addr", "", "localhost", "just an address")
+	fset.Int(&count, "count", "", 1, "just a count")
+	fset.Required("count")
+	fset.UseEnv()
+
+	err := fset.LoadJSON(strings.NewReader(`{"timeout": "30s", "name": "file", "count": 5}`))
+	failIfErr(t, err)
+
+	err = fset.Parse([]string{"-n", "cli"})
+	failIfErr(t, err)
+
+	mustEqual(t, d, 20*time.Second)
+	mustEqual(t, name, "cli")
+	mustEqual(t, addr, "localhost")
+	mustEqual(t, count, 5)
+}
+
+func TestFlagSet_ConfigReplace(t *testing.T) {
+	var name string
+	var ids []int
+	fset := NewFlagSet("testing", io.Discard)
+	fset.String(&name, "name", "n", "def", "just a name")
+	fset.IntSlice(&ids, "ids", "", nil, ",", "just ids")
+
+	failIfErr(t, fset.LoadJSON(strings.NewReader(`{"name": "system", "ids": [1, 2]}`)))
+	failIfErr(t, fset.LoadJSON(strings.NewReader(`{"n": "user"}`)))
+
+	err := fset.Parse(nil)
+	failIfErr(t, err)
+	mustEqual(t, name, "user")
+	mustEqual(t, ids, []int{1, 2})
+}
+
+func TestFlagSet_ConfigUnknownKeys(t *testing.T) {
+	var buf bytes.Buffer
+	var name string
+	fset := NewFlagSet("testing", &buf)
+	fset.String(&name, "name", "n", "def", "just a name")
+
+	err := fset.LoadJSON(strings.NewReader(`{"name": "file", "color": "red"}`))
+	mustEqual(t, err.Error(), "unknown config key color")
+
+	fset.SetUnknownKeys(UnknownKeysWarn)
+	err = fset.LoadJSON(strings.NewReader(`{"name": "file", "color": "red"}`))
+	failIfErr(t, err)
+	mustEqual(t, buf.String(), "warning: unknown config key color\n")
+
+	failIfErr(t, fset.Parse(nil))
+	mustEqual(t, name, "file")
+}
+
+func TestFlagSet_ConfigBad(t *testing.T) {
+	testCases := []struct {
+		config string
+		err    string
+	}{
+		{`{"count": [1, 2]}`, "config key count: flag -count does not accept a list"},
+		{`{"count": "x"}`, `invalid value "x" for config key count: parse error`},
+		{`{"ids": [1, "x"]}`, `invalid value "1 x" for config key ids: parsing int: strconv.ParseInt: parsing "x": invalid syntax`},
+	}
+
+	for _, tc := range testCases {
+		fset := NewFlagSet("testing", io.Discard)
+		fset.Int(new(int), "count", "", 0, "just a count")
+		fset.IntSlice(new([]int), "ids", "", nil, ",", "just ids")
+
+		err := fset.LoadJSON(strings.NewReader(tc.config))
+		if err == nil {
+			err = fset.Parse(nil)
+		}
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
+
+func TestFlagSet_LoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml":  "name: yaml\n",
+		"app.ini":   "[db]\nhost = ini\n",
+		".env":      "TESTING_PORT=5432\n",
+		"app.json":  `{"count": 3}`,
+		"app.toml":  "\nratio = 0.5\n",
+		"app.conf":  "",
+		"bad.yml":   "name: a\nname: b\n",
+		"typo.toml": "count = 1\nnmae = 2\n",
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+		failIfErr(t, err)
+	}
+
+	var name, host string
+	var port, count int
+	var ratio float64
+	var timeout time.Duration
+	fset := NewFlagSet("testing", io.Discard)
+	fset.String(&name, "name", "n", "", "just a name")
+	fset.String(&host, "db.host", "", "", "database host")
+	fset.Int(&port, "port", "", 0, "just a port")
+	fset.Int(&count, "count", "", 0, "just a count")
+	fset.Float64(&ratio, "ratio", "", 0, "just a ratio")
+	fset.Duration(&timeout, "timeout", "", 0, "just a timeout")
+
+	for _, name := range []string{"app.yaml", "app.ini", ".env", "app.json", "app.toml"} {
+		failIfErr(t, fset.LoadConfigFile(filepath.Join(dir, name)))
+	}
+	failIfErr(t, fset.Parse([]string{"-n", "cli"}))
+	mustEqual(t, name, "cli")
+	mustEqual(t, host, "ini")
+	mustEqual(t, port, 5432)
+	mustEqual(t, count, 3)
+	mustEqual(t, ratio, 0.5)
+
+	mustEqual(t, fset.Source("n").String(), "command line")
+	mustEqual(t, fset.Source("db.host"), Source{Kind: SourceConfig, Name: filepath.Join(dir, "app.ini"), Line: 2})
+	mustEqual(t, fset.Source("port").String(), filepath.Join(dir, ".env")+":1")
+	mustEqual(t, fset.Source("count").String(), filepath.Join(dir, "app.json"))
+	mustEqual(t, fset.Source("ratio").Line, 2)
+	mustEqual(t, fset.Source("timeout").String(), "default")
+	mustEqual(t, fset.Source("unknown").Kind, SourceDefault)
+
+	err := fset.LoadConfigFile(filepath.Join(dir, "app.conf"))
+	mustEqual(t, err.Error(), `unsupported config file extension ".conf"`)
+
+	err = fset.LoadConfigFile(filepath.Join(dir, "bad.yml"))
+	mustEqual(t, err.Error(), filepath.Join(dir, "bad.yml")+": parsing YAML config: line 2: key name defined twice")
+
+	err = fset.LoadConfigFile(filepath.Join(dir, "typo.toml"))
+	mustEqual(t, err.Error(), "unknown config key nmae at "+filepath.Join(dir, "typo.toml")+":2")
+
+	err = fset.LoadConfigFile(filepath.Join(dir, "missing.json"))
+	mustEqual(t, errors.Is(err, fs.ErrNotExist), true)
+}
+
+func TestFlagSet_SourceEnv(t *testing.T) {
+	t.Setenv("TESTING_TIMEOUT", "20s")
+
+	fset := NewFlagSet("testing", io.Discard)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.String(new(string), "addr", "", "", "listen address")
+	fset.String(new(string), "listen", "l", "", "old listen address")
+	fset.Deprecated("listen", "addr", true)
+	fset.UseEnv()
+
+	failIfErr(t, fset.Parse([]string{"-l", "localhost"}))
+	mustEqual(t, fset.Source("t"), Source{Kind: SourceEnv, Name: "TESTING_TIMEOUT"})
+	mustEqual(t, fset.Source("t").String(), "env TESTING_TIMEOUT")
+	mustEqual(t, fset.Source("addr").String(), "command line")
+}
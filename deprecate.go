@@ -0,0 +1,75 @@
+package flagx
+
+import (
+	"fmt"
+)
+
+// deprecation of a flag, see Deprecated.
+type deprecation struct {
+	replacement string
+	forward     bool
+}
+
+// Hidden hides the flags with the specified names or aliases from PrintDefaults,
+// the generated docs and completion scripts, the flags can still be set.
+// Hidden panics if a flag is not defined.
+func (f *FlagSet) Hidden(names ...string) {
+	for _, name := range names {
+		f.hidden[f.mustCanonical(name)] = true
+	}
+}
+
+// Deprecated marks the flag with the specified name or alias as deprecated.
+// When the flag is set, Parse prints a warning to the FlagSet output with the replacement flag, if any.
+// If forward is true the value is also set to the replacement flag, unless it is set explicitly.
+// Deprecated panics if a flag is not defined or forward is true without a replacement.
+func (f *FlagSet) Deprecated(name, replacement string, forward bool) {
+	name = f.mustCanonical(name)
+	if replacement != "" {
+		replacement = f.mustCanonical(replacement)
+	} else if forward {
+		panic("flagx: deprecated flag cannot be forwarded without a replacement")
+	}
+	f.deprecated[name] = deprecation{replacement: replacement, forward: forward}
+}
+
+// deprecationNote returns the note printed for the deprecated flag, empty if the flag is not deprecated.
+func (f *FlagSet) deprecationNote(name string) string {
+	d, ok := f.deprecated[name]
+	switch {
+	case !ok:
+		return ""
+	case d.replacement == "":
+		return "deprecated"
+	default:
+		return "deprecated, use " + f.dash(d.replacement) + d.replacement
+	}
+}
+
+// parseDeprecated warns about the deprecated flags that are set and forwards their values,
+// the flags set by a parent command are already handled by the parent.
+func (f *FlagSet) parseDeprecated() error {
+	if len(f.deprecated) == 0 {
+		return nil
+	}
+	actual := f.actual()
+	for _, name := range f.order {
+		d, ok := f.deprecated[name]
+		if !ok || !actual[name] || f.inherited[name] {
+			continue
+		}
+		fmt.Fprintf(f.fs.Output(), "warning: flag %s%s is %s\n", f.dash(name), name, f.deprecationNote(name))
+
+		// The replacement keeps its value if it comes from the command line
+		// or from a source taking precedence over the deprecated flag.
+		if src := f.sources[d.replacement]; !d.forward || src.Kind == SourceFlag || src.precedence() > f.sources[name].precedence() {
+			continue
+		}
+		value := f.fs.Lookup(name).Value.String()
+		if err := f.fs.Set(d.replacement, value); err != nil {
+			return fmt.Errorf("invalid value %q for flag %s%s: %w", value, f.dash(d.replacement), d.replacement, err)
+		}
+		f.sources[d.replacement] = f.sources[name]
+	}
+	return nil
+}
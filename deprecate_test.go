@@ -0,0 +1,98 @@
+package flagx
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_Hidden(t *testing.T) {
+	const usage = `  -timeout (-t) duration
+    	just a timeout (default 10s)
+`
+	var buf bytes.Buffer
+	var debug bool
+	fset := NewFlagSet("testing", &buf)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Bool(&debug, "debug", "d", false, "debug mode")
+	fset.Hidden("d")
+
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+
+	err := fset.Parse([]string{"-debug"})
+	failIfErr(t, err)
+	mustEqual(t, debug, true)
+
+	buf.Reset()
+	err = fset.Completion(&buf, "bash")
+	failIfErr(t, err)
+	mustEqual(t, strings.Contains(buf.String(), "-debug"), false)
+}
+
+func TestFlagSet_Deprecated(t *testing.T) {
+	const usage = `  -addr string
+    	listen address
+  -listen (-l) string
+    	old listen address (deprecated, use -addr)
+  -verbose
+    	verbose output (deprecated)
+`
+	newFlagSet := func(w io.Writer) (*FlagSet, *string, *string) {
+		var addr, listen string
+		fset := NewFlagSet("testing", w)
+		fset.String(&addr, "addr", "", "", "listen address")
+		fset.String(&listen, "listen", "l", "", "old listen address")
+		fset.Bool(new(bool), "verbose", "", false, "verbose output")
+		fset.Deprecated("l", "addr", true)
+		fset.Deprecated("verbose", "", false)
+		return fset, &addr, &listen
+	}
+
+	var buf bytes.Buffer
+	fset, _, _ := newFlagSet(&buf)
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+
+	buf.Reset()
+	fset, addr, listen := newFlagSet(&buf)
+	err := fset.Parse([]string{"-l", ":80", "-verbose"})
+	failIfErr(t, err)
+	mustEqual(t, *addr, ":80")
+	mustEqual(t, *listen, ":80")
+	mustEqual(t, buf.String(), "warning: flag -listen is deprecated, use -addr\nwarning: flag -verbose is deprecated\n")
+
+	fset, addr, _ = newFlagSet(io.Discard)
+	err = fset.Parse([]string{"-listen", ":80", "-addr", ":90"})
+	failIfErr(t, err)
+	mustEqual(t, *addr, ":90")
+}
+
+func TestFlagSet_DeprecatedPrecedence(t *testing.T) {
+	t.Setenv("TESTING_ADDR", "env")
+
+	newFlagSet := func() (*FlagSet, *string) {
+		var addr, listen string
+		fset := NewFlagSet("testing", io.Discard)
+		fset.String(&addr, "addr", "", "", "listen address")
+		fset.String(&listen, "listen", "l", "", "old listen address")
+		fset.Deprecated("l", "addr", true)
+		fset.Env("addr", "TESTING_ADDR")
+		return fset, &addr
+	}
+
+	fset, addr := newFlagSet()
+	err := fset.Parse([]string{"-l", "cli"})
+	failIfErr(t, err)
+	mustEqual(t, *addr, "cli")
+	mustEqual(t, fset.Source("addr").String(), "command line")
+
+	fset, addr = newFlagSet()
+	failIfErr(t, fset.LoadJSON(strings.NewReader(`{"listen": "file"}`)))
+	err = fset.Parse(nil)
+	failIfErr(t, err)
+	mustEqual(t, *addr, "env")
+	mustEqual(t, fset.Source("addr").String(), "env TESTING_ADDR")
+}
@@ -0,0 +1,124 @@
+package flagx
+
+import (
+	"flag"
+	"html"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Markdown writes a Markdown table of all the flags with their alias, type,
+// default value, environment variable, status and usage.
+// Every group of flags, see Group, is written as a separate table under a "###" heading.
+func (f *FlagSet) Markdown(w io.Writer) error {
+	var b strings.Builder
+	f.visitGroups(func(title string, flags []*flag.Flag) {
+		if title != "" {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString("### " + title + "\n\n")
+		}
+		b.WriteString("| Flag | Alias | Type | Default | Env | Status | Usage |\n")
+		b.WriteString("|------|-------|------|---------|-----|--------|-------|\n")
+		for _, row := range f.docRows(flags) {
+			for i, cell := range row {
+				if cell != "" && (i <= 1 || i == 3 || i == 4) {
+					cell = "`" + cell + "`"
+				}
+				b.WriteString("| " + markdownEscape(cell) + " ")
+			}
+			b.WriteString("|\n")
+		}
+	})
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// HTML writes an HTML table of all the flags with their alias, type,
+// default value, environment variable, status and usage.
+// Every group of flags, see Group, is written as a separate table under a h3 heading.
+func (f *FlagSet) HTML(w io.Writer) error {
+	var b strings.Builder
+	f.visitGroups(func(title string, flags []*flag.Flag) {
+		if title != "" {
+			b.WriteString("<h3>" + html.EscapeString(title) + "</h3>\n")
+		}
+		b.WriteString("<table>\n<thead>\n<tr><th>Flag</th><th>Alias</th><th>Type</th><th>Default</th><th>Env</th><th>Status</th><th>Usage</th></tr>\n</thead>\n<tbody>\n")
+		for _, row := range f.docRows(flags) {
+			b.WriteString("<tr>")
+			for i, cell := range row {
+				cell = html.EscapeString(cell)
+				if cell != "" && (i <= 1 || i == 3 || i == 4) {
+					cell = "<code>" + cell + "</code>"
+				}
+				b.WriteString("<td>" + strings.ReplaceAll(cell, "\n", "<br>") + "</td>")
+			}
+			b.WriteString("</tr>\n")
+		}
+		b.WriteString("</tbody>\n</table>\n")
+	})
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// docRows returns the table cells for every flag: name, alias, type, default, env, status and usage.
+func (f *FlagSet) docRows(flags []*flag.Flag) [][]string {
+	var rows [][]string
+	for _, fl := range flags {
+		_, usage := flag.UnquoteUsage(fl)
+		def, _ := defaultValue(fl)
+		alias := f.aliases[fl.Name]
+		if alias != "" {
+			alias = f.dash(alias) + alias
+		}
+		rows = append(rows, []string{
+			f.dash(fl.Name) + fl.Name,
+			alias,
+			flagType(fl),
+			def,
+			f.EnvName(fl.Name),
+			strings.Join(f.status(fl.Name), ", "),
+			usage,
+		})
+	}
+	return rows
+}
+
+// status returns the markers of the flag like "required".
+func (f *FlagSet) status(name string) []string {
+	var status []string
+	if f.required[name] {
+		status = append(status, "required")
+	}
+	if note := f.deprecationNote(name); note != "" {
+		status = append(status, note)
+	}
+	return status
+}
+
+// flagType returns the Go type of the flag value, like "int" or "[]time.Duration",
+// the placeholder from flag.UnquoteUsage is used if the value does not implement flag.Getter.
+func flagType(fl *flag.Flag) string {
+	if v, ok := fl.Value.(*specValue); ok {
+		return v.typ
+	}
+	if g, ok := fl.Value.(flag.Getter); ok {
+		if v := g.Get(); v != nil {
+			t := reflect.TypeOf(v)
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			return t.String()
+		}
+	}
+	name, _ := flag.UnquoteUsage(&flag.Flag{Value: fl.Value})
+	return name
+}
+
+// markdownEscape escapes s to be used in a Markdown table cell.
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
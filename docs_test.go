@@ -0,0 +1,66 @@
+package flagx
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_Markdown(t *testing.T) {
+	const table = "| Flag | Alias | Type | Default | Env | Status | Usage |\n" +
+		"|------|-------|------|---------|-----|--------|-------|\n" +
+		"| `-hook` |  | value |  |  |  | just a hook |\n" +
+		"| `-ids` |  | []int | `1,2` |  |  | just ids<br>in a list |\n" +
+		"| `-ip` |  | net.IP | `127.0.0.1` |  |  | <address> |\n" +
+		"| `-name` |  | string | `\"x\"` |  | required | just a name \\| id |\n" +
+		"| `-tags` |  | map[string]struct {} |  |  |  | just tags |\n" +
+		"| `-timeout` | `-t` | time.Duration | `10s` | `MY_TIMEOUT` |  | just a timeout |\n"
+
+	var buf bytes.Buffer
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.String(new(string), "name", "", "x", "just a `name` | id")
+	fset.IntSlice(new([]int), "ids", "", []int{1, 2}, ",", "just ids\nin a list")
+	fset.StringSet(new(map[string]struct{}), "tags", "", nil, "just tags")
+	fset.Text(new(net.IP), "ip", "", net.IPv4(127, 0, 0, 1), "<address>")
+	fset.Func("hook", "", "just a hook", func(string) error { return nil })
+	fset.Required("name")
+	fset.Env("timeout", "MY_TIMEOUT")
+
+	err := fset.Markdown(&buf)
+	failIfErr(t, err)
+	mustEqual(t, buf.String(), table)
+}
+
+func TestFlagSet_HTML(t *testing.T) {
+	const table = `<table>
+<thead>
+<tr><th>Flag</th><th>Alias</th><th>Type</th><th>Default</th><th>Env</th><th>Status</th><th>Usage</th></tr>
+</thead>
+<tbody>
+<tr><td><code>-hook</code></td><td></td><td>value</td><td></td><td></td><td></td><td>just a hook</td></tr>
+<tr><td><code>-ids</code></td><td></td><td>[]int</td><td><code>1,2</code></td><td></td><td></td><td>just ids<br>in a list</td></tr>
+<tr><td><code>-ip</code></td><td></td><td>net.IP</td><td><code>127.0.0.1</code></td><td></td><td></td><td>&lt;address&gt;</td></tr>
+<tr><td><code>-name</code></td><td></td><td>string</td><td><code>&#34;x&#34;</code></td><td></td><td>required</td><td>just a name | id</td></tr>
+<tr><td><code>-tags</code></td><td></td><td>map[string]struct {}</td><td></td><td></td><td></td><td>just tags</td></tr>
+<tr><td><code>-timeout</code></td><td><code>-t</code></td><td>time.Duration</td><td><code>10s</code></td><td><code>MY_TIMEOUT</code></td><td></td><td>just a timeout</td></tr>
+</tbody>
+</table>
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.String(new(string), "name", "", "x", "just a `name` | id")
+	fset.IntSlice(new([]int), "ids", "", []int{1, 2}, ",", "just ids\nin a list")
+	fset.StringSet(new(map[string]struct{}), "tags", "", nil, "just tags")
+	fset.Text(new(net.IP), "ip", "", net.IPv4(127, 0, 0, 1), "<address>")
+	fset.Func("hook", "", "just a hook", func(string) error { return nil })
+	fset.Required("name")
+	fset.Env("timeout", "MY_TIMEOUT")
+
+	err := fset.HTML(&buf)
+	failIfErr(t, err)
+	mustEqual(t, buf.String(), table)
+}
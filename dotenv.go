@@ -0,0 +1,88 @@
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoadDotenv loads flag values from the .env file read from r, like LoadJSON.
+// Must be called before Parse.
+//
+// Lines are KEY=VALUE, optionally prefixed with "export", where KEY is the environment variable
+// of a flag as bound by Env or derived by UseEnv, with or without the env prefix, even if UseEnv is not called.
+// The flags unbound by Env with an empty key are not set from the file.
+// Lines starting with '#' are comments, so is the text after " #" in unquoted values.
+// Values in single quotes are literal, values in double quotes may contain the escapes
+// \n, \r, \t, \", \\ and \$, quoted values may span several lines.
+// The variables set in the environment take precedence over the file only for the flags
+// bound to them by UseEnv or Env, see Parse.
+func (f *FlagSet) LoadDotenv(r io.Reader) error {
+	return f.loadConfig(r, "", parseDotenv)
+}
+
+var dotenvReplacer = strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`, `\$`, "$")
+
+// parseDotenv returns the values of the .env file.
+func parseDotenv(src []byte) ([]configValue, error) {
+	var values []configValue
+	lines := strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		num := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if rest := strings.TrimPrefix(line, "export"); rest != line && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || strings.ContainsAny(strings.TrimSpace(line[:eq]), " \t") {
+			return nil, fmt.Errorf("parsing dotenv config: line %d: expected KEY=VALUE", num)
+		}
+		key := strings.TrimSpace(line[:eq])
+		value := strings.TrimSpace(line[eq+1:])
+
+		switch {
+		case value != "" && (value[0] == '"' || value[0] == '\''):
+			quote := value[0]
+			value = value[1:]
+			end := dotenvQuoteEnd(value, quote)
+			for end < 0 {
+				i++
+				if i >= len(lines) {
+					return nil, fmt.Errorf("parsing dotenv config: line %d: unterminated quoted value", num)
+				}
+				value += "\n" + lines[i]
+				end = dotenvQuoteEnd(value, quote)
+			}
+			if rest := strings.TrimSpace(value[end+1:]); rest != "" && rest[0] != '#' {
+				return nil, fmt.Errorf("parsing dotenv config: line %d: unexpected %q after value", i+1, rest)
+			}
+			value = value[:end]
+			if quote == '"' {
+				value = dotenvReplacer.Replace(value)
+			}
+		default:
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = strings.TrimSpace(value[:j])
+			}
+		}
+		values = append(values, configValue{key: key, values: []string{value}, env: true, line: num})
+	}
+	return values, nil
+}
+
+// dotenvQuoteEnd returns the index of the closing quote in s, -1 if none.
+func dotenvQuoteEnd(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,71 @@
+package flagx
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_LoadDotenv(t *testing.T) {
+	t.Setenv("TESTING_PORT", "8080")
+
+	const config = `# local settings
+TESTING_NAME=plain value # a comment
+export TESTING_TIMEOUT=3s
+TESTING_PORT=443
+DB_HOST='literal \n $HOME'
+MOTD="multi
+line\t\"quoted\""
+MY_TAGS="a,b"
+`
+
+	var name, host, motd string
+	var timeout time.Duration
+	var port int
+	var tags []string
+	fset := NewFlagSet("testing", io.Discard)
+	fset.String(&name, "name", "", "", "just a name")
+	fset.Duration(&timeout, "timeout", "t", 0, "just a timeout")
+	fset.Int(&port, "port", "", 80, "just a port")
+	fset.String(&host, "db.host", "", "", "database host")
+	fset.String(&motd, "motd", "", "", "message of the day")
+	fset.StringSlice(&tags, "tags", "", nil, ",", "just tags")
+	fset.UseEnv()
+	fset.Env("tags", "MY_TAGS")
+
+	err := fset.LoadDotenv(strings.NewReader(config))
+	failIfErr(t, err)
+	failIfErr(t, fset.Parse(nil))
+
+	mustEqual(t, name, "plain value")
+	mustEqual(t, timeout, 3*time.Second)
+	mustEqual(t, port, 8080)
+	mustEqual(t, host, `literal \n $HOME`)
+	mustEqual(t, motd, "multi\nline\t\"quoted\"")
+	mustEqual(t, tags, []string{"a", "b"})
+}
+
+func TestFlagSet_LoadDotenvBad(t *testing.T) {
+	testCases := []struct {
+		config string
+		err    string
+	}{
+		{"NAME", "parsing dotenv config: line 1: expected KEY=VALUE"},
+		{"\nMY NAME=x", "parsing dotenv config: line 2: expected KEY=VALUE"},
+		{"NAME=\"open\n", "parsing dotenv config: line 1: unterminated quoted value"},
+		{"NAME='a' b", `parsing dotenv config: line 1: unexpected "b" after value`},
+		{"OTHER=x", "unknown config key OTHER at line 1"},
+		{"HOST=x", "unknown config key HOST at line 1"},
+	}
+
+	for _, tc := range testCases {
+		fset := NewFlagSet("testing", io.Discard)
+		fset.String(new(string), "name", "", "", "just a name")
+		fset.String(new(string), "host", "", "", "just a host")
+		fset.Env("host", "")
+
+		err := fset.LoadDotenv(strings.NewReader(tc.config))
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
@@ -0,0 +1,126 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// UseEnv binds every flag in the set to an environment variable.
+// The variable name is the flag name upper-cased, with every character that is
+// not a letter or a digit replaced by '_', and prefixed with the env prefix.
+// The default prefix is derived from the FlagSet name in the same way,
+// so flag "timeout" in FlagSet "testing" is bound to TESTING_TIMEOUT.
+// Values from the command line take precedence over the environment.
+func (f *FlagSet) UseEnv() {
+	f.useEnv = true
+}
+
+// SetEnvPrefix sets the prefix for environment variables bound by UseEnv.
+// Empty prefix means variables are named after the flags only.
+func (f *FlagSet) SetEnvPrefix(prefix string) {
+	f.envPrefix = envKey(prefix, "")
+}
+
+// Env binds the flag with the specified name or alias to the environment variable key.
+// It works regardless of UseEnv and overrides the variable derived from the prefix.
+// Empty key means the flag is not bound to any variable.
+// Env panics if the flag is not defined.
+func (f *FlagSet) Env(name, key string) {
+	f.envs[f.mustCanonical(name)] = key
+}
+
+// EnvName returns the environment variable bound to the flag with the specified name,
+// empty string means the flag is not bound.
+func (f *FlagSet) EnvName(name string) string {
+	name, ok := f.canonical(name)
+	if !ok {
+		return ""
+	}
+	if key, ok := f.envs[name]; ok {
+		return key
+	}
+	if !f.useEnv {
+		return ""
+	}
+	return envKey(name, f.envPrefix)
+}
+
+// parseEnv sets the flags that were not set on the command line from the environment.
+func (f *FlagSet) parseEnv() error {
+	actual := f.actual()
+	var err error
+	f.visitFlags(func(fl *flag.Flag) {
+		if actual[fl.Name] || err != nil {
+			return
+		}
+		key := f.EnvName(fl.Name)
+		if key == "" {
+			return
+		}
+		value, ok := os.LookupEnv(key)
+		if !ok || value == "" {
+			return
+		}
+		if errSet := f.fs.Set(fl.Name, value); errSet != nil {
+			err = fmt.Errorf("invalid value %q for env %s: %w", value, key, errSet)
+			return
+		}
+		f.sources[fl.Name] = Source{Kind: SourceEnv, Name: key}
+	})
+	return err
+}
+
+// envFlag returns the name of the flag bound to the environment variable key by Env,
+// or whose variable would be key with UseEnv, with or without the env prefix.
+// The flags unbound by Env with an empty key are skipped.
+func (f *FlagSet) envFlag(key string) (string, bool) {
+	for _, name := range f.order {
+		if k, ok := f.envs[name]; ok {
+			if k != "" && k == key {
+				return name, true
+			}
+			continue
+		}
+		if key == envKey(name, f.envPrefix) || key == envKey(name, "") {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// actual returns the names of the flags that have been set, aliases are reported by their flag name.
+func (f *FlagSet) actual() map[string]bool {
+	actual := make(map[string]bool, len(f.preset))
+	for name := range f.preset {
+		actual[name] = true
+	}
+	f.Visit(func(fl *flag.Flag) {
+		if name, ok := f.canonical(fl.Name); ok {
+			actual[name] = true
+		}
+	})
+	return actual
+}
+
+// envKey returns s as an environment variable name with the given prefix.
+func envKey(s, prefix string) string {
+	if s == "" {
+		return prefix
+	}
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	return prefix + key
+}
@@ -0,0 +1,66 @@
+package flagx
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_UseEnv(t *testing.T) {
+	t.Setenv("TESTING_TIMEOUT", "20s")
+	t.Setenv("TESTING_IDS", "4,5")
+	t.Setenv("TESTING_NAME", "env")
+
+	var d time.Duration
+	var ids []int
+	var name string
+	fset := NewFlagSet("testing", os.Stderr)
+	fset.Duration(&d, "timeout", "t", 10*time.Second, "just a timeout")
+	fset.IntSlice(&ids, "ids", "", []int{1}, ",", "just ids")
+	fset.String(&name, "name", "n", "def", "just a name")
+	fset.UseEnv()
+
+	err := fset.Parse([]string{"-n", "cli"})
+	failIfErr(t, err)
+
+	mustEqual(t, d, 20*time.Second)
+	mustEqual(t, ids, []int{4, 5})
+	mustEqual(t, name, "cli")
+}
+
+func TestFlagSet_Env(t *testing.T) {
+	t.Setenv("TESTING_TIMEOUT", "20s")
+	t.Setenv("MY_TIMEOUT", "30s")
+	t.Setenv("APP_COUNT", "3")
+
+	var d time.Duration
+	var count int
+	fset := NewFlagSet("testing", os.Stderr)
+	fset.Duration(&d, "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Int(&count, "count", "c", 1, "just a count")
+	fset.Env("t", "MY_TIMEOUT")
+
+	mustEqual(t, fset.EnvName("count"), "")
+	fset.UseEnv()
+	fset.SetEnvPrefix("app")
+	mustEqual(t, fset.EnvName("timeout"), "MY_TIMEOUT")
+	mustEqual(t, fset.EnvName("c"), "APP_COUNT")
+
+	err := fset.Parse(nil)
+	failIfErr(t, err)
+
+	mustEqual(t, d, 30*time.Second)
+	mustEqual(t, count, 3)
+}
+
+func TestFlagSet_EnvBad(t *testing.T) {
+	t.Setenv("TESTING_COUNT", "abc")
+
+	fset := NewFlagSet("testing", os.Stderr)
+	fset.Int(new(int), "count", "c", 1, "just a count")
+	fset.UseEnv()
+
+	if err := fset.Parse(nil); err == nil {
+		t.Fatal("must fail")
+	}
+}
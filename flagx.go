@@ -7,7 +7,9 @@ import (
 	"io"
 	"reflect"
 	"regexp"
-	"strings"
+	"sort"
+	"strconv"
+	"text/template"
 	"time"
 )
 
@@ -21,32 +23,144 @@ type Getter = flag.Getter
 type FlagSet struct {
 	fs      *flag.FlagSet
 	aliases map[string]string // a mapping from a flag's name to its alias, empty value means no alias is defined.
+	order   []string          // the names of the flags in definition order.
+
+	sortOrder SortOrder
+	sortLess  func(a, b *flag.Flag) bool
+
+	positional    *FlagSet // the positional arguments, nil if none is declared.
+	epilog        string
+	usageTemplate *template.Template
+	color         ColorMode
+	width         int // the width used to wrap the usage, see SetWidth.
+	groups        []group
+	groupOf       map[string]string // a mapping from a flag's name to its group title.
+	description   string
+	examples      []Example
+
+	config      []configValue // the values loaded from the configuration files.
+	unknownKeys UnknownKeys
+
+	useEnv    bool
+	envPrefix string
+	envs      map[string]string // a mapping from a flag's name to its environment variable, empty value means no variable is bound.
+
+	mode          ParseMode
+	interspersed  bool
+	errorHandling flag.ErrorHandling
+
+	completionFlag  string
+	completionShell string                // the value of the completion flag, see EnableCompletion.
+	completions     map[string]completion // a mapping from a flag's name to its value completion.
+
+	hidden     map[string]bool        // the names of the hidden flags.
+	deprecated map[string]deprecation // a mapping from a flag's name to its deprecation.
+
+	required    map[string]bool // the names of the required flags.
+	constraints []constraint
+	preset      map[string]bool   // the names of the flags set outside the stdlib FlagSet, like by a parent command.
+	inherited   map[string]bool   // the names of the flags set by a parent command.
+	sources     map[string]Source // a mapping from a flag's name to the source of its value.
 }
 
 // NewFlagSet returns new FlagSet.
+// Parse returns the errors, see SetErrorHandling, and prints PrintUsage on help and
+// invalid arguments, see SetUsage.
 func NewFlagSet(name string, output io.Writer) *FlagSet {
 	fs := flag.NewFlagSet(name, flag.ContinueOnError)
 	fs.SetOutput(output)
-	return &FlagSet{
-		fs:      fs,
-		aliases: make(map[string]string),
+	f := &FlagSet{
+		fs:          fs,
+		aliases:     make(map[string]string),
+		envPrefix:   envKey(name, ""),
+		groupOf:     make(map[string]string),
+		envs:        make(map[string]string),
+		completions: make(map[string]completion),
+		hidden:      make(map[string]bool),
+		deprecated:  make(map[string]deprecation),
+		required:    make(map[string]bool),
+		preset:      make(map[string]bool),
+		inherited:   make(map[string]bool),
+		sources:     make(map[string]Source),
 	}
+	fs.Usage = f.PrintUsage
+	return f
 }
 
 // AsStdlib returns *flag.FlagSet with all flags.
 // Note: aliases are duplicated.
 func (f *FlagSet) AsStdlib() *flag.FlagSet { return f.fs }
 
-func (f *FlagSet) NFlag() int                     { return f.fs.NFlag() }
-func (f *FlagSet) NArg() int                      { return f.fs.NArg() }
-func (f *FlagSet) Arg(i int) string               { return f.fs.Arg(i) }
-func (f *FlagSet) Args() []string                 { return f.fs.Args() }
-func (f *FlagSet) IsParsed() bool                 { return f.fs.Parsed() }
-func (f *FlagSet) Parse(arguments []string) error { return f.fs.Parse(arguments) }
-func (f *FlagSet) VisitAll(fn func(*flag.Flag))   { f.fs.VisitAll(fn) }
-func (f *FlagSet) Visit(fn func(*flag.Flag))      { f.fs.Visit(fn) }
-func (f *FlagSet) Lookup(name string) *flag.Flag  { return f.fs.Lookup(name) }
-func (f *FlagSet) Set(name, value string) error   { return f.fs.Set(name, value) }
+func (f *FlagSet) NFlag() int                    { return f.fs.NFlag() }
+func (f *FlagSet) NArg() int                     { return f.fs.NArg() }
+func (f *FlagSet) Arg(i int) string              { return f.fs.Arg(i) }
+func (f *FlagSet) Args() []string                { return f.fs.Args() }
+func (f *FlagSet) IsParsed() bool                { return f.fs.Parsed() }
+func (f *FlagSet) VisitAll(fn func(*flag.Flag))  { f.fs.VisitAll(fn) }
+func (f *FlagSet) Visit(fn func(*flag.Flag))     { f.fs.Visit(fn) }
+func (f *FlagSet) Lookup(name string) *flag.Flag { return f.fs.Lookup(name) }
+func (f *FlagSet) Set(name, value string) error  { return f.fs.Set(name, value) }
+
+// Parse parses flag definitions from the argument list, which should not
+// include the command name. Flags that are not present in the argument list
+// are looked up in the environment, see UseEnv and Env, then in the loaded
+// configuration files, see LoadConfigFile.
+// Must be called after all flags in the FlagSet are defined and before flags are accessed by the program.
+// The return value will be flag.ErrHelp if -help or -h were set but not defined.
+// An error is returned if a required flag is not set, see Required.
+// On errors Parse behaves as set by SetErrorHandling.
+func (f *FlagSet) Parse(arguments []string) error {
+	err := f.parse(arguments)
+	if err == nil {
+		if err = f.validate(nil); err != nil {
+			f.fail(err)
+		}
+	}
+	return f.handleError(err)
+}
+
+// parse sets the flags from the arguments and the environment without validating them.
+func (f *FlagSet) parse(arguments []string) error {
+	f.completionShell = ""
+	if f.completionFlag != "" && len(arguments) > 0 && arguments[0] == completeCommand {
+		for _, v := range f.completeWords(arguments[1:]) {
+			fmt.Fprintln(completionOutput, v)
+		}
+		return flag.ErrHelp
+	}
+	if f.mode == StdlibMode && !f.interspersed {
+		if err := f.parseStdlib(arguments); err != nil {
+			return err
+		}
+	} else {
+		args, err := f.parseArgs(arguments)
+		if err != nil {
+			return err
+		}
+		// Let the stdlib FlagSet store the positional arguments and mark itself as parsed.
+		if err := f.fs.Parse(append([]string{"--"}, args...)); err != nil {
+			return err
+		}
+	}
+	if f.completionShell != "" {
+		return f.writeCompletion(f.completionShell)
+	}
+	f.fs.Visit(func(fl *flag.Flag) {
+		if name, ok := f.canonical(fl.Name); ok {
+			f.sources[name] = Source{Kind: SourceFlag}
+		}
+	})
+	if err := f.parseEnv(); err != nil {
+		return f.fail(err)
+	}
+	if err := f.parseConfig(); err != nil {
+		return f.fail(err)
+	}
+	if err := f.parseDeprecated(); err != nil {
+		return f.fail(err)
+	}
+	return f.parsePositional()
+}
 
 // Var defines a flag with the specified name and usage string. The type and
 // value of the flag are represented by the first argument, of type Value, which
@@ -56,7 +170,7 @@ func (f *FlagSet) Set(name, value string) error   { return f.fs.Set(name, value)
 // decompose the comma-separated string into the slice.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Var(value flag.Value, name, alias, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.Var(value, name, usage)
 	if alias != "" {
 		f.fs.Var(value, alias, usage)
@@ -68,7 +182,7 @@ func (f *FlagSet) Var(value flag.Value, name, alias, usage string) {
 // If fn returns a non-nil error, it will be treated as a flag value parsing error.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Func(name, alias, usage string, fn func(string) error) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.Func(name, usage, fn)
 	if alias != "" {
 		f.fs.Func(alias, usage, fn)
@@ -90,7 +204,7 @@ func (f *FlagSet) Text(p encoding.TextUnmarshaler, name, alias string, value enc
 // The argument p points to a bool variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Bool(p *bool, name, alias string, value bool, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.BoolVar(p, name, value, usage)
 	if alias != "" {
 		f.fs.BoolVar(p, alias, value, usage)
@@ -101,7 +215,7 @@ func (f *FlagSet) Bool(p *bool, name, alias string, value bool, usage string) {
 // The argument p points to an int variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Int(p *int, name, alias string, value int, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.IntVar(p, name, value, usage)
 	if alias != "" {
 		f.fs.IntVar(p, alias, value, usage)
@@ -112,7 +226,7 @@ func (f *FlagSet) Int(p *int, name, alias string, value int, usage string) {
 // The argument p points to an int64 variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Int64(p *int64, name, alias string, value int64, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.Int64Var(p, name, value, usage)
 	if alias != "" {
 		f.fs.Int64Var(p, alias, value, usage)
@@ -123,7 +237,7 @@ func (f *FlagSet) Int64(p *int64, name, alias string, value int64, usage string)
 // The argument p points to a uint variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Uint(p *uint, name, alias string, value uint, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.UintVar(p, name, value, usage)
 	if alias != "" {
 		f.fs.UintVar(p, alias, value, usage)
@@ -134,7 +248,7 @@ func (f *FlagSet) Uint(p *uint, name, alias string, value uint, usage string) {
 // The argument p points to a uint64 variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Uint64(p *uint64, name, alias string, value uint64, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.Uint64Var(p, name, value, usage)
 	if alias != "" {
 		f.fs.Uint64Var(p, alias, value, usage)
@@ -145,7 +259,7 @@ func (f *FlagSet) Uint64(p *uint64, name, alias string, value uint64, usage stri
 // The argument p points to a string variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) String(p *string, name, alias, value, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.StringVar(p, name, value, usage)
 	if alias != "" {
 		f.fs.StringVar(p, alias, value, usage)
@@ -156,7 +270,7 @@ func (f *FlagSet) String(p *string, name, alias, value, usage string) {
 // The argument p points to a float64 variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Float64(p *float64, name, alias string, value float64, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.Float64Var(p, name, value, usage)
 	if alias != "" {
 		f.fs.Float64Var(p, alias, value, usage)
@@ -168,7 +282,7 @@ func (f *FlagSet) Float64(p *float64, name, alias string, value float64, usage s
 // The flag accepts a value acceptable to time.ParseDuration.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Duration(p *time.Duration, name, alias string, value time.Duration, usage string) {
-	f.aliases[name] = alias
+	f.addName(name, alias)
 	f.fs.DurationVar(p, name, value, usage)
 	if alias != "" {
 		f.fs.DurationVar(p, alias, value, usage)
@@ -282,12 +396,12 @@ func (f *FlagSet) DurationSlice(p *[]time.Duration, name, alias string, value []
 // The argument p points to an int variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) IntSet(p *map[int]struct{}, name, alias string, value map[int]struct{}, usage string) {
-	f.aliases[name] = alias
-	var si SetOfInt = value
-	*p = map[int]struct{}(si)
-	f.fs.Var(&si, name, usage)
+	f.addName(name, alias)
+	*p = value
+	si := (*SetOfInt)(p)
+	f.fs.Var(si, name, usage)
 	if alias != "" {
-		f.fs.Var(&si, alias, usage)
+		f.fs.Var(si, alias, usage)
 	}
 }
 
@@ -295,12 +409,12 @@ func (f *FlagSet) IntSet(p *map[int]struct{}, name, alias string, value map[int]
 // The argument p points to an int64 variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Int64Set(p *map[int64]struct{}, name, alias string, value map[int64]struct{}, usage string) {
-	f.aliases[name] = alias
-	var si SetOfInt64 = value
-	*p = map[int64]struct{}(si)
-	f.fs.Var(&si, name, usage)
+	f.addName(name, alias)
+	*p = value
+	si := (*SetOfInt64)(p)
+	f.fs.Var(si, name, usage)
 	if alias != "" {
-		f.fs.Var(&si, alias, usage)
+		f.fs.Var(si, alias, usage)
 	}
 }
 
@@ -308,12 +422,12 @@ func (f *FlagSet) Int64Set(p *map[int64]struct{}, name, alias string, value map[
 // The argument p points to a uint variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) UintSet(p *map[uint]struct{}, name, alias string, value map[uint]struct{}, usage string) {
-	f.aliases[name] = alias
-	var su SetOfUint = value
-	*p = map[uint]struct{}(su)
-	f.fs.Var(&su, name, usage)
+	f.addName(name, alias)
+	*p = value
+	su := (*SetOfUint)(p)
+	f.fs.Var(su, name, usage)
 	if alias != "" {
-		f.fs.Var(&su, alias, usage)
+		f.fs.Var(su, alias, usage)
 	}
 }
 
@@ -321,12 +435,12 @@ func (f *FlagSet) UintSet(p *map[uint]struct{}, name, alias string, value map[ui
 // The argument p points to a uint64 variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Uint64Set(p *map[uint64]struct{}, name, alias string, value map[uint64]struct{}, usage string) {
-	f.aliases[name] = alias
-	var su SetOfUint64 = value
-	*p = map[uint64]struct{}(su)
-	f.fs.Var(&su, name, usage)
+	f.addName(name, alias)
+	*p = value
+	su := (*SetOfUint64)(p)
+	f.fs.Var(su, name, usage)
 	if alias != "" {
-		f.fs.Var(&su, alias, usage)
+		f.fs.Var(su, alias, usage)
 	}
 }
 
@@ -334,12 +448,12 @@ func (f *FlagSet) Uint64Set(p *map[uint64]struct{}, name, alias string, value ma
 // The argument p points to a string variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) StringSet(p *map[string]struct{}, name, alias string, value map[string]struct{}, usage string) {
-	f.aliases[name] = alias
-	var ss SetOfString = value
-	*p = map[string]struct{}(ss)
-	f.fs.Var(&ss, name, usage)
+	f.addName(name, alias)
+	*p = value
+	ss := (*SetOfString)(p)
+	f.fs.Var(ss, name, usage)
 	if alias != "" {
-		f.fs.Var(&ss, alias, usage)
+		f.fs.Var(ss, alias, usage)
 	}
 }
 
@@ -347,12 +461,12 @@ func (f *FlagSet) StringSet(p *map[string]struct{}, name, alias string, value ma
 // The argument p points to a float64 variable in which to store the value of the flag.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) Float64Set(p *map[float64]struct{}, name, alias string, value map[float64]struct{}, usage string) {
-	f.aliases[name] = alias
-	var sf SetOfFloat64 = value
-	*p = map[float64]struct{}(sf)
-	f.fs.Var(&sf, name, usage)
+	f.addName(name, alias)
+	*p = value
+	sf := (*SetOfFloat64)(p)
+	f.fs.Var(sf, name, usage)
 	if alias != "" {
-		f.fs.Var(&sf, alias, usage)
+		f.fs.Var(sf, alias, usage)
 	}
 }
 
@@ -361,73 +475,83 @@ func (f *FlagSet) Float64Set(p *map[float64]struct{}, name, alias string, value
 // The flag accepts a value acceptable to time.ParseDuration.
 // Empty string for alias means no alias will be created.
 func (f *FlagSet) DurationSet(p *map[time.Duration]struct{}, name, alias string, value map[time.Duration]struct{}, usage string) {
-	f.aliases[name] = alias
-	var sd SetOfDuration = value
-	*p = map[time.Duration]struct{}(sd)
-	f.fs.Var(&sd, name, usage)
+	f.addName(name, alias)
+	*p = value
+	sd := (*SetOfDuration)(p)
+	f.fs.Var(sd, name, usage)
 	if alias != "" {
-		f.fs.Var(&sd, alias, usage)
+		f.fs.Var(sd, alias, usage)
 	}
 }
 
 // PrintDefaults prints, to standard error unless configured otherwise, the
 // default values of all defined command-line flags in the set.
+// The output is rendered with the "defaults" template, see SetUsageTemplate.
 func (f *FlagSet) PrintDefaults() {
-	// NOTE(junk1tm): copy-pasted from flag.PrintDefaults with a few modifications to support aliases.
+	f.printTemplate("defaults")
+}
 
-	var isZeroValueErrs []error
-	f.VisitAll(func(fl *flag.Flag) {
-		if _, ok := f.aliases[fl.Name]; !ok {
-			// The flag is an alias, do not print it separately.
-			return
-		}
-		var b strings.Builder
-		fmt.Fprintf(&b, "  -%s", fl.Name) // Two spaces before -; see next two comments.
-		if alias := f.aliases[fl.Name]; alias != "" {
-			fmt.Fprintf(&b, " (-%s)", alias)
-		}
-		name, usage := flag.UnquoteUsage(fl)
-		if len(name) > 0 {
-			b.WriteString(" ")
-			b.WriteString(name)
-		}
-		// Boolean flags of one ASCII letter are so common we
-		// treat them specially, putting their usage on the same line.
-		if b.Len() <= 4 { // space, space, '-', 'x'.
-			b.WriteString("\t")
-		} else {
-			// Four spaces before the tab triggers good alignment
-			// for both 4- and 8-space tab stops.
-			b.WriteString("\n    \t")
-		}
-		b.WriteString(strings.ReplaceAll(usage, "\n", "\n    \t"))
-
-		// Print the default value only if it differs to the zero value
-		// for this flag type.
-		if isZero, err := isZeroValue(fl, fl.DefValue); err != nil {
-			isZeroValueErrs = append(isZeroValueErrs, err)
-		} else if !isZero {
-			// HACK(junk1tm): flag.stringValue is unexported, so we have to compare the type's name.
-			if fmt.Sprintf("%T", fl.Value) == "*flag.stringValue" {
-				// put quotes on the value
-				fmt.Fprintf(&b, " (default %q)", fl.DefValue)
-			} else {
-				fmt.Fprintf(&b, " (default %v)", fl.DefValue)
-			}
-		}
-		fmt.Fprint(f.fs.Output(), b.String(), "\n")
-	})
-	// If calling String on any zero flag.Values triggered a panic, print
-	// the messages after the full set of defaults so that the programmer
-	// knows to fix the panic.
-	if errs := isZeroValueErrs; len(errs) > 0 {
-		fmt.Fprintln(f.fs.Output())
-		for _, err := range errs {
-			fmt.Fprintln(f.fs.Output(), err)
-		}
+// SortOrder defines the order of the flags in PrintDefaults and the generated docs.
+type SortOrder int
+
+const (
+	// SortAlphabetical sorts the flags by name, like VisitAll does.
+	SortAlphabetical SortOrder = iota
+
+	// SortDefinition keeps the flags in the order they were defined.
+	SortDefinition
+)
+
+// SetSortOrder sets the order of the flags in PrintDefaults and the generated docs.
+// Default is SortAlphabetical.
+func (f *FlagSet) SetSortOrder(order SortOrder) {
+	f.sortOrder = order
+	f.sortLess = nil
+}
+
+// SetSortFunc sets the order of the flags in PrintDefaults and the generated docs to the custom comparator,
+// flags reported as equal keep the definition order. Aliases are not passed to less.
+func (f *FlagSet) SetSortFunc(less func(a, b *flag.Flag) bool) {
+	f.sortLess = less
+}
+
+// visitFlags visits the flags in the order set by SetSortOrder or SetSortFunc,
+// aliases are not visited separately.
+func (f *FlagSet) visitFlags(fn func(*flag.Flag)) {
+	flags := make([]*flag.Flag, 0, len(f.order))
+	for _, name := range f.order {
+		flags = append(flags, f.fs.Lookup(name))
+	}
+
+	switch {
+	case f.sortLess != nil:
+		sort.SliceStable(flags, func(i, j int) bool { return f.sortLess(flags[i], flags[j]) })
+	case f.sortOrder == SortAlphabetical:
+		sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	}
+
+	for _, fl := range flags {
+		fn(fl)
 	}
 }
 
+// defaultValue returns the default value of the flag as printed by PrintDefaults,
+// empty if it is the zero value for this flag type.
+func defaultValue(fl *flag.Flag) (string, error) {
+	// Print the default value only if it differs to the zero value
+	// for this flag type.
+	isZero, err := isZeroValue(fl, fl.DefValue)
+	if err != nil || isZero {
+		return "", err
+	}
+	// HACK(junk1tm): flag.stringValue is unexported, so we have to compare the type's name.
+	if fmt.Sprintf("%T", fl.Value) == "*flag.stringValue" {
+		// put quotes on the value
+		return strconv.Quote(fl.DefValue), nil
+	}
+	return fl.DefValue, nil
+}
+
 func isZeroValue(fl *flag.Flag, value string) (ok bool, err error) {
 	// NOTE(junk1tm): copy-pasted from flag.isZeroValue as a part of flag.PrintDefaults.
 
@@ -461,3 +585,33 @@ func panicIfEmpty(sep string) {
 		panic("flagx: slice separator must not be empty")
 	}
 }
+
+// addName records the flag name and its alias.
+func (f *FlagSet) addName(name, alias string) {
+	if _, ok := f.aliases[name]; !ok {
+		f.order = append(f.order, name)
+	}
+	f.aliases[name] = alias
+}
+
+// canonical returns the name of the flag defined with the given name or alias.
+func (f *FlagSet) canonical(name string) (string, bool) {
+	if _, ok := f.aliases[name]; ok {
+		return name, true
+	}
+	for n, alias := range f.aliases {
+		if alias != "" && alias == name {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// mustCanonical is like canonical but panics if the flag is not defined.
+func (f *FlagSet) mustCanonical(name string) string {
+	n, ok := f.canonical(name)
+	if !ok {
+		panic(fmt.Sprintf("flagx: flag %s is not defined", name))
+	}
+	return n
+}
@@ -59,3 +59,47 @@ func mustEqual(tb testing.TB, have, want interface{}) {
 		tb.Fatalf("\nhave: %v\nwant: %v", have, want)
 	}
 }
+
+func TestFlagSet_SortOrder(t *testing.T) {
+	const alphabetical = `  -addr string
+    	listen address
+  -timeout (-t) duration
+    	just a timeout (default 10s)
+  -zone string
+    	just a zone
+`
+	const definition = `  -zone string
+    	just a zone
+  -timeout (-t) duration
+    	just a timeout (default 10s)
+  -addr string
+    	listen address
+`
+	const custom = `  -timeout (-t) duration
+    	just a timeout (default 10s)
+  -zone string
+    	just a zone
+  -addr string
+    	listen address
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.String(new(string), "zone", "", "", "just a zone")
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.String(new(string), "addr", "", "", "listen address")
+
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), alphabetical)
+
+	buf.Reset()
+	fset.SetSortOrder(SortDefinition)
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), definition)
+
+	buf.Reset()
+	fset.SetSortFunc(func(a, b *flag.Flag) bool {
+		return a.DefValue != "" && b.DefValue == ""
+	})
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), custom)
+}
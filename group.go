@@ -0,0 +1,74 @@
+package flagx
+
+import (
+	"flag"
+)
+
+// group of the flags with a title, see Group.
+type group struct {
+	title string
+	names []string
+}
+
+// Group assigns the flags with the specified names or aliases to the group with the given title,
+// like "Network". PrintDefaults and the generated docs render the flags without a group first,
+// followed by every group under its title, groups are rendered in the order of the first Group call.
+// A flag belongs to a single group, Group panics if a flag is not defined.
+func (f *FlagSet) Group(title string, names ...string) {
+	idx := -1
+	for i, g := range f.groups {
+		if g.title == title {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		f.groups = append(f.groups, group{title: title})
+		idx = len(f.groups) - 1
+	}
+
+	for _, name := range names {
+		name = f.mustCanonical(name)
+		if old, ok := f.groupOf[name]; ok {
+			for i, g := range f.groups {
+				if g.title == old {
+					f.groups[i].names = removeString(g.names, name)
+				}
+			}
+		}
+		f.groupOf[name] = title
+		f.groups[idx].names = append(f.groups[idx].names, name)
+	}
+}
+
+// visitGroups calls fn for the flags without a group, with empty title,
+// and then for every group with its flags, ordered as by visitFlags.
+// Hidden flags and empty groups are skipped.
+func (f *FlagSet) visitGroups(fn func(title string, flags []*flag.Flag)) {
+	byTitle := make(map[string][]*flag.Flag)
+	f.visitFlags(func(fl *flag.Flag) {
+		if f.hidden[fl.Name] {
+			return
+		}
+		title := f.groupOf[fl.Name]
+		byTitle[title] = append(byTitle[title], fl)
+	})
+
+	if flags := byTitle[""]; len(flags) > 0 {
+		fn("", flags)
+	}
+	for _, g := range f.groups {
+		if flags := byTitle[g.title]; len(flags) > 0 && g.title != "" {
+			fn(g.title, flags)
+		}
+	}
+}
+
+func removeString(ss []string, s string) []string {
+	res := ss[:0]
+	for _, v := range ss {
+		if v != s {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+package flagx
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_GroupPrintDefaults(t *testing.T) {
+	const usage = `  -verbose (-v)
+    	verbose output
+
+Network:
+  -addr (-a) string
+    	listen address (default ":8080")
+
+Storage:
+  -dir string
+    	data dir
+
+Debug:
+  -timeout duration
+    	just a timeout
+  -trace
+    	enable tracing
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("my-app", &buf)
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.String(new(string), "addr", "a", ":8080", "listen address")
+	fset.Duration(new(time.Duration), "timeout", "", 0, "just a timeout")
+	fset.String(new(string), "dir", "", "", "data dir")
+	fset.Bool(new(bool), "trace", "", false, "enable tracing")
+	fset.Group("Network", "addr", "timeout")
+	fset.Group("Storage", "dir")
+	fset.Group("Debug", "trace", "timeout")
+
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+}
+
+func TestFlagSet_GroupDocs(t *testing.T) {
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.String(new(string), "addr", "a", ":8080", "listen address")
+	fset.Duration(new(time.Duration), "timeout", "", 0, "just a timeout")
+	fset.String(new(string), "dir", "", "", "data dir")
+	fset.Bool(new(bool), "trace", "", false, "enable tracing")
+	fset.Group("Network", "addr", "timeout")
+	fset.Group("Storage", "dir")
+	fset.Group("Debug", "trace", "timeout")
+
+	var buf bytes.Buffer
+	err := fset.Markdown(&buf)
+	failIfErr(t, err)
+	mustEqual(t, strings.Contains(buf.String(), "| `-verbose` | `-v` |"), true)
+	mustEqual(t, strings.Contains(buf.String(), "\n### Network\n\n| Flag |"), true)
+	mustEqual(t, strings.Index(buf.String(), "### Storage") < strings.Index(buf.String(), "### Debug"), true)
+
+	buf.Reset()
+	err = fset.HTML(&buf)
+	failIfErr(t, err)
+	mustEqual(t, strings.Contains(buf.String(), "<h3>Debug</h3>\n<table>"), true)
+
+	buf.Reset()
+	err = fset.ManPage(&buf, 1)
+	failIfErr(t, err)
+	mustEqual(t, strings.Contains(buf.String(), ".SS Storage\n.TP\n\\fB\\-dir\\fR \\fIstring\\fR\n"), true)
+
+	spec := fset.Spec()
+	mustEqual(t, spec.Flags[3].Name, "trace")
+	mustEqual(t, spec.Flags[3].Group, "Debug")
+
+	imported, err := NewFlagSetFromSpec(spec, io.Discard)
+	failIfErr(t, err)
+	mustEqual(t, imported.groupOf, fset.groupOf)
+}
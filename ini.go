@@ -0,0 +1,66 @@
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoadINI loads flag values from the INI file read from r, like LoadJSON.
+// Must be called before Parse.
+//
+// Lines are "key = value" or "key: value", sections map to dotted names, so "host"
+// in [db] sets -db.host. Lines starting with ';' or '#' are comments. Values are
+// trimmed and may be enclosed in double or single quotes, which are removed.
+// A key repeated in a section sets a slice or set flag with all its values.
+func (f *FlagSet) LoadINI(r io.Reader) error {
+	return f.loadConfig(r, "", parseINI)
+}
+
+// parseINI returns the values of the INI file.
+func parseINI(src []byte) ([]configValue, error) {
+	var values []configValue
+	index := make(map[string]int) // a mapping from a key to its value.
+	section := ""
+	for i, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+			continue
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("parsing INI config: line %d: expected ]", i+1)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("parsing INI config: line %d: empty section name", i+1)
+			}
+			section = name + "."
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep <= 0 {
+			return nil, fmt.Errorf("parsing INI config: line %d: expected key = value", i+1)
+		}
+		key := section + strings.TrimSpace(line[:sep])
+		value := unquote(strings.TrimSpace(line[sep+1:]))
+
+		if j, ok := index[key]; ok {
+			values[j].values = append(values[j].values, value)
+			values[j].list = true
+			continue
+		}
+		index[key] = len(values)
+		values = append(values, configValue{key: key, values: []string{value}, line: i + 1})
+	}
+	return values, nil
+}
+
+// unquote removes the matching double or single quotes around s.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package flagx
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_LoadINI(t *testing.T) {
+	const config = `; flagx config
+name = "quoted value "
+verbose: true
+
+[db]
+host = db.local
+timeout = 3s
+# repeated keys
+hosts = one
+hosts = 'two'
+`
+
+	var name, host string
+	var verbose bool
+	var timeout time.Duration
+	var hosts []string
+	fset := NewFlagSet("testing", io.Discard)
+	fset.String(&name, "name", "", "", "just a name")
+	fset.Bool(&verbose, "verbose", "v", false, "verbose output")
+	fset.String(&host, "db.host", "", "", "database host")
+	fset.Duration(&timeout, "db.timeout", "", 0, "database timeout")
+	fset.StringSlice(&hosts, "db.hosts", "", nil, ",", "database hosts")
+
+	err := fset.LoadINI(strings.NewReader(config))
+	failIfErr(t, err)
+	failIfErr(t, fset.Parse(nil))
+
+	mustEqual(t, name, "quoted value ")
+	mustEqual(t, verbose, true)
+	mustEqual(t, host, "db.local")
+	mustEqual(t, timeout, 3*time.Second)
+	mustEqual(t, hosts, []string{"one", "two"})
+}
+
+func TestFlagSet_LoadINIBad(t *testing.T) {
+	testCases := []struct {
+		config string
+		err    string
+	}{
+		{"[db", "parsing INI config: line 1: expected ]"},
+		{"\n[ ]", "parsing INI config: line 2: empty section name"},
+		{"name", "parsing INI config: line 1: expected key = value"},
+		{"[db]\nname = x", "unknown config key db.name at line 2"},
+		{"name = a\nname = b", "config key name at line 1: flag -name does not accept a list"},
+	}
+
+	for _, tc := range testCases {
+		fset := NewFlagSet("testing", io.Discard)
+		fset.String(new(string), "name", "", "", "just a name")
+
+		err := fset.LoadINI(strings.NewReader(tc.config))
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
@@ -0,0 +1,93 @@
+package flagx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// LoadJSON loads flag values from the JSON object read from r, Parse applies them to
+// the flags not set on the command line or from the environment. Must be called before Parse.
+//
+// Keys are flag names or aliases, nested objects map to dotted names, so {"db": {"host": "x"}}
+// sets -db.host. Arrays set slice and set flags, null values are ignored, other values
+// are passed to flag.Value.Set as in the command line, strings unquoted.
+// Keys that match no flag are handled as set by SetUnknownKeys.
+// An error is returned if r contains anything but a single JSON object.
+func (f *FlagSet) LoadJSON(r io.Reader) error {
+	return f.loadConfig(r, "", parseJSON)
+}
+
+// parseJSON returns the values of the JSON object.
+func parseJSON(src []byte) ([]configValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("parsing JSON config: %w", err)
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("parsing JSON config: top-level value must be an object")
+	}
+	if err := dec.Decode(new(interface{})); err != io.EOF {
+		return nil, fmt.Errorf("parsing JSON config: unexpected data after the top-level object")
+	}
+
+	var values []configValue
+	if err := jsonValues(&values, "", obj); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// jsonValues appends the values of the JSON object with their keys prefixed with prefix.
+func jsonValues(values *[]configValue, prefix string, obj map[string]interface{}) error {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := prefix + k
+		switch v := obj[k].(type) {
+		case nil:
+		case map[string]interface{}:
+			if err := jsonValues(values, key+".", v); err != nil {
+				return err
+			}
+		case []interface{}:
+			list := make([]string, 0, len(v))
+			for _, elem := range v {
+				s, ok := jsonScalar(elem)
+				if !ok {
+					return fmt.Errorf("config key %s: arrays must contain only strings, numbers and booleans", key)
+				}
+				list = append(list, s)
+			}
+			*values = append(*values, configValue{key: key, values: list, list: true})
+		default:
+			s, _ := jsonScalar(v)
+			*values = append(*values, configValue{key: key, values: []string{s}})
+		}
+	}
+	return nil
+}
+
+// jsonScalar returns the string, number or boolean as a flag value.
+func jsonScalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,72 @@
+package flagx
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_LoadJSON(t *testing.T) {
+	const config = `{
+		"db": {"host": "db.local", "port": 5432, "timeout": "3s"},
+		"v": true,
+		"tags": ["a", "b,c"],
+		"ids": [1, 2],
+		"ratio": 0.5,
+		"name": null
+	}`
+
+	var host, name string
+	var port int
+	var timeout time.Duration
+	var verbose bool
+	var tags []string
+	var ids map[int]struct{}
+	var ratio float64
+	fset := NewFlagSet("testing", io.Discard)
+	fset.String(&host, "db.host", "", "", "database host")
+	fset.Int(&port, "db.port", "", 0, "database port")
+	fset.Duration(&timeout, "db.timeout", "", 0, "database timeout")
+	fset.Bool(&verbose, "verbose", "v", false, "verbose output")
+	fset.StringSlice(&tags, "tags", "", nil, ",", "just tags")
+	fset.IntSet(&ids, "ids", "", nil, "just ids")
+	fset.Float64(&ratio, "ratio", "", 0, "just a ratio")
+	fset.String(&name, "name", "", "def", "just a name")
+
+	err := fset.LoadJSON(strings.NewReader(config))
+	failIfErr(t, err)
+	failIfErr(t, fset.Parse(nil))
+
+	mustEqual(t, host, "db.local")
+	mustEqual(t, port, 5432)
+	mustEqual(t, timeout, 3*time.Second)
+	mustEqual(t, verbose, true)
+	mustEqual(t, tags, []string{"a", "b,c"})
+	mustEqual(t, ids, map[int]struct{}{1: {}, 2: {}})
+	mustEqual(t, ratio, 0.5)
+	mustEqual(t, name, "def")
+}
+
+func TestFlagSet_LoadJSONBad(t *testing.T) {
+	testCases := []struct {
+		config string
+		err    string
+	}{
+		{`{"name": `, "parsing JSON config: unexpected EOF"},
+		{`["name"]`, "parsing JSON config: top-level value must be an object"},
+		{`{"name": "a"} x`, "parsing JSON config: unexpected data after the top-level object"},
+		{`{"name": "a"}{"name": "b"}`, "parsing JSON config: unexpected data after the top-level object"},
+		{`{"tags": [["a"]]}`, "config key tags: arrays must contain only strings, numbers and booleans"},
+		{`{"name": {"first": "a"}}`, "unknown config key name.first"},
+	}
+
+	for _, tc := range testCases {
+		fset := NewFlagSet("testing", io.Discard)
+		fset.String(new(string), "name", "", "", "just a name")
+		fset.StringSlice(new([]string), "tags", "", nil, ",", "just tags")
+
+		err := fset.LoadJSON(strings.NewReader(tc.config))
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
@@ -0,0 +1,157 @@
+package flagx
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// durationPattern matches the strings accepted by time.ParseDuration.
+const durationPattern = `^(0|[-+]?([0-9]*(\.[0-9]*)?(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// JSONSchema writes a JSON Schema (draft 2020-12) describing the configuration accepted by the FlagSet:
+// an object with a property for every flag, dotted names like "db.timeout" become nested objects.
+// Slices are arrays, sets are arrays of unique items, durations are strings matching time.ParseDuration.
+// An error is returned if a flag name is also the prefix of a dotted name, like "db" and "db.timeout".
+func (f *FlagSet) JSONSchema(w io.Writer) error {
+	root := newSchemaObject()
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = f.fs.Name()
+	if f.description != "" {
+		root["description"] = f.description
+	}
+
+	var err error
+	nested := make(map[string]string) // a mapping from a dotted prefix to the first flag nested under it.
+	f.visitFlags(func(fl *flag.Flag) {
+		if err != nil {
+			return
+		}
+		parts := strings.Split(fl.Name, ".")
+		obj := root
+		for i, part := range parts[:len(parts)-1] {
+			prefix := strings.Join(parts[:i+1], ".")
+			props := obj["properties"].(map[string]interface{})
+			next, ok := props[part].(map[string]interface{})
+			switch {
+			case !ok:
+				next = newSchemaObject()
+				props[part] = next
+				nested[prefix] = fl.Name
+			case next["properties"] == nil:
+				err = fmt.Errorf("flagx: flag %s conflicts with the nested flag %s", prefix, fl.Name)
+				return
+			}
+			obj = next
+		}
+
+		name := parts[len(parts)-1]
+		props := obj["properties"].(map[string]interface{})
+		if _, ok := props[name]; ok {
+			err = fmt.Errorf("flagx: flag %s conflicts with the nested flag %s", fl.Name, nested[fl.Name])
+			return
+		}
+		props[name] = flagSchema(fl)
+		if f.required[fl.Name] {
+			required, _ := obj["required"].([]string)
+			obj["required"] = append(required, name)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(root)
+}
+
+func newSchemaObject() map[string]interface{} {
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           map[string]interface{}{},
+		"additionalProperties": false,
+	}
+}
+
+// flagSchema returns the schema of the flag value.
+func flagSchema(fl *flag.Flag) map[string]interface{} {
+	_, usage := flag.UnquoteUsage(fl)
+	schema := map[string]interface{}{}
+	if usage != "" {
+		schema["description"] = usage
+	}
+
+	var t reflect.Type
+	if g, ok := fl.Value.(flag.Getter); ok && g.Get() != nil {
+		t = reflect.TypeOf(g.Get())
+	}
+
+	var items map[string]interface{}
+	switch {
+	case t == nil:
+		schema["type"] = "string"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		items = typeSchema(t.Elem())
+		schema["type"] = "array"
+		schema["items"] = items
+	case t.Kind() == reflect.Map:
+		items = typeSchema(t.Key())
+		schema["type"] = "array"
+		schema["items"] = items
+		schema["uniqueItems"] = true
+	default:
+		for k, v := range typeSchema(t) {
+			schema[k] = v
+		}
+	}
+
+	if def, _ := defaultValue(fl); def != "" {
+		if items == nil {
+			schema["default"] = schemaValue(schema["type"], fl.DefValue)
+		} else {
+			parts := strings.Split(fl.DefValue, separator(fl.Value))
+			values := make([]interface{}, len(parts))
+			for i, part := range parts {
+				values[i] = schemaValue(items["type"], part)
+			}
+			schema["default"] = values
+		}
+	}
+	return schema
+}
+
+// typeSchema returns the schema of the scalar Go type.
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return map[string]interface{}{"type": "string", "pattern": durationPattern}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// schemaValue converts the flag value string to a JSON value of the schema type.
+func schemaValue(typ interface{}, s string) interface{} {
+	switch typ {
+	case "boolean", "integer", "number":
+		if json.Valid([]byte(s)) {
+			return json.RawMessage(s)
+		}
+	}
+	return s
+}
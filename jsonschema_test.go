@@ -0,0 +1,119 @@
+package flagx
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_JSONSchema(t *testing.T) {
+	const schema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "additionalProperties": false,
+  "properties": {
+    "db": {
+      "additionalProperties": false,
+      "properties": {
+        "hosts": {
+          "description": "db hosts",
+          "items": {
+            "type": "string"
+          },
+          "type": "array"
+        },
+        "timeout": {
+          "default": "5s",
+          "description": "db timeout",
+          "pattern": "^(0|[-+]?([0-9]*(\\.[0-9]*)?(ns|us|µs|μs|ms|s|m|h))+)$",
+          "type": "string"
+        }
+      },
+      "required": [
+        "timeout"
+      ],
+      "type": "object"
+    },
+    "ids": {
+      "default": [
+        1,
+        2
+      ],
+      "description": "just ids",
+      "items": {
+        "type": "integer"
+      },
+      "type": "array"
+    },
+    "ip": {
+      "default": "127.0.0.1",
+      "description": "just an ip",
+      "type": "string"
+    },
+    "name": {
+      "description": "just a name",
+      "type": "string"
+    },
+    "ports": {
+      "description": "just ports",
+      "items": {
+        "minimum": 0,
+        "type": "integer"
+      },
+      "type": "array",
+      "uniqueItems": true
+    },
+    "ratio": {
+      "default": 0.5,
+      "description": "just a ratio",
+      "type": "number"
+    },
+    "verbose": {
+      "description": "verbose output",
+      "type": "boolean"
+    }
+  },
+  "required": [
+    "name"
+  ],
+  "title": "my-app",
+  "type": "object"
+}
+`
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.String(new(string), "name", "n", "", "just a name")
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.Float64(new(float64), "ratio", "", 0.5, "just a ratio")
+	fset.IntSlice(new([]int), "ids", "", []int{1, 2}, ";", "just ids")
+	fset.UintSet(new(map[uint]struct{}), "ports", "", nil, "just ports")
+	fset.Text(new(net.IP), "ip", "", net.IPv4(127, 0, 0, 1), "just an ip")
+	fset.Duration(new(time.Duration), "db.timeout", "", 5*time.Second, "db timeout")
+	fset.StringSlice(new([]string), "db.hosts", "", nil, ",", "db hosts")
+	fset.Required("name", "db.timeout")
+
+	var buf bytes.Buffer
+	err := fset.JSONSchema(&buf)
+	failIfErr(t, err)
+	mustEqual(t, buf.String(), schema)
+}
+
+func TestFlagSet_JSONSchemaConflict(t *testing.T) {
+	testCases := []struct {
+		order SortOrder
+		err   string
+	}{
+		{SortAlphabetical, "flagx: flag db conflicts with the nested flag db.timeout"},
+		{SortDefinition, "flagx: flag db conflicts with the nested flag db.timeout"},
+	}
+
+	for _, tc := range testCases {
+		fset := NewFlagSet("testing", io.Discard)
+		fset.Duration(new(time.Duration), "db.timeout", "", 0, "database timeout")
+		fset.String(new(string), "db", "", "", "database name")
+		fset.SetSortOrder(tc.order)
+
+		err := fset.JSONSchema(io.Discard)
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
@@ -0,0 +1,140 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Example of the program usage, see AddExample.
+type Example struct {
+	Command     string
+	Description string
+}
+
+// SetDescription sets the description of the program used in the generated documentation,
+// the first line is used as a summary.
+func (f *FlagSet) SetDescription(description string) {
+	f.description = description
+}
+
+// AddExample adds an example of the program usage to the generated documentation.
+func (f *FlagSet) AddExample(command, description string) {
+	f.examples = append(f.examples, Example{Command: command, Description: description})
+}
+
+// ManPage writes a roff man page with NAME, SYNOPSIS, DESCRIPTION, OPTIONS, ARGUMENTS,
+// ENVIRONMENT and EXAMPLES sections, empty sections are omitted.
+// The FlagSet name is used as the program name.
+func (f *FlagSet) ManPage(w io.Writer, section int) error {
+	var b strings.Builder
+	name := f.fs.Name()
+	summary, description := f.description, ""
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary, description = summary[:i], strings.TrimSpace(summary[i+1:])
+	}
+
+	fmt.Fprintf(&b, ".TH %s %d\n", strings.ToUpper(roffEscape(name)), section)
+	b.WriteString(".SH NAME\n")
+	b.WriteString(roffEscape(name))
+	if summary != "" {
+		b.WriteString(` \- ` + roffEscape(summary))
+	}
+	b.WriteString("\n.SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n[\\fIOPTIONS\\fR]", roffEscape(name))
+	if synopsis := f.Synopsis(); synopsis != "" {
+		fmt.Fprintf(&b, " %s", roffEscape(synopsis))
+	}
+	b.WriteString("\n")
+	if description != "" {
+		b.WriteString(".SH DESCRIPTION\n")
+		b.WriteString(roffParagraphs(description))
+	}
+
+	b.WriteString(".SH OPTIONS\n")
+	var envs []string
+	f.visitGroups(func(title string, flags []*flag.Flag) {
+		if title != "" {
+			b.WriteString(".SS " + roffEscape(title) + "\n")
+		}
+		for _, fl := range flags {
+			names := []string{fl.Name}
+			if alias := f.aliases[fl.Name]; alias != "" {
+				names = append(names, alias)
+			}
+			for i, n := range names {
+				names[i] = `\fB` + roffEscape(f.dash(n)+n) + `\fR`
+			}
+			f.manItem(&b, fl, strings.Join(names, ", "))
+
+			if key := f.EnvName(fl.Name); key != "" {
+				envs = append(envs, fmt.Sprintf(".TP\n.B %s\nSets \\fB%s\\fR.\n", roffEscape(key), roffEscape(f.dash(fl.Name)+fl.Name)))
+			}
+		}
+	})
+
+	if p := f.positional; p != nil && len(p.order) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, n := range p.order {
+			p.manItem(&b, p.fs.Lookup(n), `\fI`+roffEscape(p.positionalName(n))+`\fR`)
+		}
+	}
+
+	if len(envs) > 0 {
+		b.WriteString(".SH ENVIRONMENT\n")
+		b.WriteString(strings.Join(envs, ""))
+	}
+
+	if len(f.examples) > 0 {
+		b.WriteString(".SH EXAMPLES\n")
+		for _, e := range f.examples {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s", roffEscape(e.Command), roffParagraphs(e.Description))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// manItem writes the flag as a tagged paragraph with its placeholder, usage and default value.
+func (f *FlagSet) manItem(b *strings.Builder, fl *flag.Flag, names string) {
+	placeholder, usage := flag.UnquoteUsage(fl)
+	b.WriteString(".TP\n" + names)
+	if placeholder != "" {
+		b.WriteString(` \fI` + roffEscape(placeholder) + `\fR`)
+	}
+	b.WriteString("\n" + roffEscape(usage))
+	if def, _ := defaultValue(fl); def != "" {
+		b.WriteString(" (default " + roffEscape(def) + ")")
+	}
+	if f.required[fl.Name] {
+		b.WriteString(" (required)")
+	}
+	if note := f.deprecationNote(fl.Name); note != "" {
+		b.WriteString(" (" + roffEscape(note) + ")")
+	}
+	b.WriteString("\n")
+}
+
+// roffParagraphs escapes s and separates its paragraphs with .PP requests.
+func roffParagraphs(s string) string {
+	paragraphs := strings.Split(s, "\n\n")
+	for i, p := range paragraphs {
+		paragraphs[i] = roffEscape(strings.TrimSpace(p)) + "\n"
+	}
+	return strings.Join(paragraphs, ".PP\n")
+}
+
+// roffEscape escapes s to be used as a roff text.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
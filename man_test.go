@@ -0,0 +1,63 @@
+package flagx
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_ManPage(t *testing.T) {
+	const page = `.TH MY\-APP 1
+.SH NAME
+my\-app \- just an app
+.SH SYNOPSIS
+.B my\-app
+[\fIOPTIONS\fR] src [files...]
+.SH DESCRIPTION
+It does things.
+.PP
+\&.and more things.
+.SH OPTIONS
+.TP
+\fB\-name\fR \fIstring\fR
+just a name (default "x\e\e")
+.TP
+\fB\-timeout\fR, \fB\-t\fR \fIduration\fR
+just a timeout (default 10s) (required)
+.TP
+\fB\-verbose\fR, \fB\-v\fR
+verbose output
+.SH ARGUMENTS
+.TP
+\fIsrc\fR \fIstring\fR
+source (required)
+.TP
+\fI[files...]\fR \fIvalue\fR
+files to process
+.SH ENVIRONMENT
+.TP
+.B MY_APP_TIMEOUT
+Sets \fB\-timeout\fR.
+.SH EXAMPLES
+.TP
+.B my\-app \-t 20s src
+Run with a timeout.
+`
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.SetDescription("just an app\nIt does things.\n\n.and more things.")
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+	fset.String(new(string), "name", "", `x\`, "just a name")
+	fset.Required("timeout")
+	fset.Env("timeout", "MY_APP_TIMEOUT")
+	fset.Positional().String(new(string), "src", "", "", "source")
+	fset.Positional().StringSlice(new([]string), "files", "", nil, ",", "files to process")
+	fset.Positional().Required("src")
+	fset.AddExample("my-app -t 20s src", "Run with a timeout.")
+
+	var buf bytes.Buffer
+	err := fset.ManPage(&buf, 1)
+	failIfErr(t, err)
+	mustEqual(t, buf.String(), page)
+}
@@ -0,0 +1,260 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ParseMode defines how the arguments are parsed.
+type ParseMode int
+
+const (
+	// StdlibMode parses the arguments like the flag package does:
+	// -flag and --flag are the same, short flags cannot be bundled.
+	StdlibMode ParseMode = iota
+
+	// GNUMode parses the arguments following the GNU/POSIX conventions:
+	// names are long options (--timeout, --timeout=20s, --timeout 20s)
+	// and one-letter names or aliases are short options which can be
+	// bundled and take attached values (-vx, -t20s, -vt 20s).
+	GNUMode
+)
+
+// SetMode sets the mode used by Parse.
+func (f *FlagSet) SetMode(mode ParseMode) {
+	f.mode = mode
+}
+
+// SetInterspersed sets whether Parse continues to look for flags after the first positional argument,
+// like in "tool file1 -v file2". The positional arguments are collected into Args, "--" terminates the flags.
+// Works in every ParseMode.
+func (f *FlagSet) SetInterspersed(interspersed bool) {
+	f.interspersed = interspersed
+}
+
+// SetErrorHandling sets how Parse behaves if parsing or validation fails:
+// flag.ContinueOnError returns the error (the default), flag.ExitOnError exits
+// with status 0 for -help and 2 otherwise, flag.PanicOnError panics with the error.
+func (f *FlagSet) SetErrorHandling(errorHandling flag.ErrorHandling) {
+	f.errorHandling = errorHandling
+}
+
+// ErrorHandling returns the error handling behavior of the flag set.
+func (f *FlagSet) ErrorHandling() flag.ErrorHandling {
+	return f.errorHandling
+}
+
+// SetUsage sets the function called on -help and when parsing fails,
+// nil restores the default PrintUsage.
+func (f *FlagSet) SetUsage(usage func()) {
+	if usage == nil {
+		usage = f.PrintUsage
+	}
+	f.fs.Usage = usage
+}
+
+// handleError applies the error handling behavior to the error returned by Parse.
+func (f *FlagSet) handleError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch f.errorHandling {
+	case flag.ExitOnError:
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	case flag.PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// boolFlag is an optional interface to indicate boolean flags, see flag.Value.
+type boolFlag interface {
+	flag.Value
+	IsBoolFlag() bool
+}
+
+func isBoolFlag(v flag.Value) bool {
+	b, ok := v.(boolFlag)
+	return ok && b.IsBoolFlag()
+}
+
+// dash returns the prefix used for the flag name in the current mode.
+func (f *FlagSet) dash(name string) string {
+	if f.mode == GNUMode && len(name) > 1 {
+		return "--"
+	}
+	return "-"
+}
+
+// parseArgs sets the flags from the arguments and returns the positional arguments.
+func (f *FlagSet) parseArgs(arguments []string) ([]string, error) {
+	var args []string
+	for i := 0; i < len(arguments); i++ {
+		arg := arguments[i]
+		switch {
+		case arg == "--":
+			return append(args, arguments[i+1:]...), nil
+
+		case f.mode == StdlibMode && len(arg) > 1 && arg[0] == '-':
+			name := arg[1:]
+			if name[0] == '-' {
+				name = name[1:]
+			}
+			next, err := f.parseLong("-", name, arguments[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			i += next
+
+		case strings.HasPrefix(arg, "--"):
+			next, err := f.parseLong("--", arg[2:], arguments[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			i += next
+
+		case len(arg) > 1 && arg[0] == '-':
+			next, err := f.parseShort(arg[1:], arguments[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			i += next
+
+		case f.interspersed:
+			args = append(args, arg)
+
+		default:
+			return append(args, arguments[i:]...), nil
+		}
+	}
+	return args, nil
+}
+
+// parseLong parses a long option like "name" or "name=value" given with the dash prefix,
+// returns the number of consumed arguments from rest.
+func (f *FlagSet) parseLong(dash, arg string, rest []string) (int, error) {
+	name, value, hasValue := arg, "", false
+	if i := strings.Index(arg, "="); i >= 0 {
+		name, value, hasValue = arg[:i], arg[i+1:], true
+	}
+	if name == "" || name[0] == '-' {
+		return 0, f.failf("bad flag syntax: %s%s", dash, arg)
+	}
+
+	fl := f.fs.Lookup(name)
+	if fl == nil {
+		if name == "help" || name == "h" {
+			f.usage()
+			return 0, flag.ErrHelp
+		}
+		return 0, f.failf("flag provided but not defined: %s%s", dash, name)
+	}
+
+	var next int
+	switch {
+	case hasValue:
+	case isBoolFlag(fl.Value):
+		value = "true"
+	case len(rest) > 0:
+		value, next = rest[0], 1
+	default:
+		return 0, f.failf("flag needs an argument: %s%s", dash, name)
+	}
+	if err := f.fs.Set(name, value); err != nil {
+		return 0, f.failf("invalid value %q for flag %s%s: %v", value, dash, name, err)
+	}
+	return next, nil
+}
+
+// parseShort parses bundled short options like "vx", "t20s" or "t=20s",
+// returns the number of consumed arguments from rest.
+func (f *FlagSet) parseShort(arg string, rest []string) (int, error) {
+	for i := 0; i < len(arg); i++ {
+		name := arg[i : i+1]
+		fl := f.fs.Lookup(name)
+		if fl == nil {
+			if name == "h" {
+				f.usage()
+				return 0, flag.ErrHelp
+			}
+			return 0, f.failf("flag provided but not defined: -%s", name)
+		}
+
+		if isBoolFlag(fl.Value) {
+			value := "true"
+			if strings.HasPrefix(arg[i+1:], "=") {
+				value, i = arg[i+2:], len(arg)
+			}
+			if err := f.fs.Set(name, value); err != nil {
+				return 0, f.failf("invalid value %q for flag -%s: %v", value, name, err)
+			}
+			continue
+		}
+
+		value, next := strings.TrimPrefix(arg[i+1:], "="), 0
+		if i+1 == len(arg) {
+			if len(rest) == 0 {
+				return 0, f.failf("flag needs an argument: -%s", name)
+			}
+			value, next = rest[0], 1
+		}
+		if err := f.fs.Set(name, value); err != nil {
+			return 0, f.failf("invalid value %q for flag -%s: %v", value, name, err)
+		}
+		return next, nil
+	}
+	return 0, nil
+}
+
+// parseStdlib parses the arguments with the stdlib FlagSet, the error is printed by FlagSet
+// to be colored, see SetColor.
+func (f *FlagSet) parseStdlib(arguments []string) error {
+	output, usage := f.fs.Output(), f.fs.Usage
+	var usageCalled bool
+	f.fs.SetOutput(io.Discard)
+	f.fs.Usage = func() { usageCalled = true }
+	err := f.fs.Parse(arguments)
+	f.fs.SetOutput(output)
+	f.fs.Usage = usage
+
+	if !usageCalled {
+		return err
+	}
+	if err != flag.ErrHelp {
+		f.printError(err)
+	}
+	f.usage()
+	return err
+}
+
+// failf prints the error and the usage, like the stdlib FlagSet does, and returns the error.
+func (f *FlagSet) failf(format string, a ...interface{}) error {
+	return f.fail(fmt.Errorf(format, a...))
+}
+
+// fail prints the error and the usage and returns the error.
+func (f *FlagSet) fail(err error) error {
+	f.printError(err)
+	f.usage()
+	return err
+}
+
+// printError prints the error to the output, colored if enabled.
+func (f *FlagSet) printError(err error) {
+	msg := err.Error()
+	if f.colors() {
+		msg = colorize("error", msg)
+	}
+	fmt.Fprintln(f.fs.Output(), msg)
+}
+
+// usage calls the usage function of the stdlib FlagSet, like its Parse does.
+func (f *FlagSet) usage() {
+	f.fs.Usage()
+}
@@ -0,0 +1,255 @@
+package flagx
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_GNUMode(t *testing.T) {
+	testCases := []struct {
+		args    []string
+		verbose bool
+		extract bool
+		file    string
+		timeout time.Duration
+		rest    []string
+	}{
+		{[]string{"-vxf", "file", "arg"}, true, true, "file", 0, []string{"arg"}},
+		{[]string{"-t20s", "--file=x"}, false, false, "x", 20 * time.Second, []string{}},
+		{[]string{"-t=1s", "-v", "--", "-x"}, true, false, "", time.Second, []string{"-x"}},
+		{[]string{"--timeout", "2s", "--verbose=false", "a", "-x"}, false, false, "", 2 * time.Second, []string{"a", "-x"}},
+		{[]string{"-xv", "-ffile"}, true, true, "file", 0, []string{}},
+	}
+
+	for _, tc := range testCases {
+		var verbose, extract bool
+		var file string
+		var timeout time.Duration
+		fset := NewFlagSet("testing", io.Discard)
+		fset.SetMode(GNUMode)
+		fset.Bool(&verbose, "verbose", "v", false, "verbose output")
+		fset.Bool(&extract, "extract", "x", false, "extract files")
+		fset.String(&file, "file", "f", "", "archive file")
+		fset.Duration(&timeout, "timeout", "t", 0, "just a timeout")
+
+		err := fset.Parse(tc.args)
+		failIfErr(t, err)
+
+		mustEqual(t, verbose, tc.verbose)
+		mustEqual(t, extract, tc.extract)
+		mustEqual(t, file, tc.file)
+		mustEqual(t, timeout, tc.timeout)
+		mustEqual(t, fset.Args(), tc.rest)
+	}
+}
+
+func TestFlagSet_GNUModeBad(t *testing.T) {
+	testCases := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-vy"}, "flag provided but not defined: -y"},
+		{[]string{"--unknown"}, "flag provided but not defined: --unknown"},
+		{[]string{"-vf"}, "flag needs an argument: -f"},
+		{[]string{"--file"}, "flag needs an argument: --file"},
+		{[]string{"---file"}, "bad flag syntax: ---file"},
+		{[]string{"-t", "abc"}, `invalid value "abc" for flag -t: parse error`},
+	}
+
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		fset := NewFlagSet("testing", &buf)
+		fset.SetMode(GNUMode)
+		fset.Bool(new(bool), "verbose", "v", false, "verbose output")
+		fset.String(new(string), "file", "f", "", "archive file")
+		fset.Duration(new(time.Duration), "timeout", "t", 0, "just a timeout")
+
+		err := fset.Parse(tc.args)
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
+
+func TestFlagSet_GNUModeHelp(t *testing.T) {
+	fset := NewFlagSet("testing", io.Discard)
+	fset.SetMode(GNUMode)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+
+	for _, arg := range []string{"--help", "-h"} {
+		err := fset.Parse([]string{arg})
+		if !errors.Is(err, flag.ErrHelp) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFlagSet_GNUModePrintDefaults(t *testing.T) {
+	const usage = `  --timeout (-t) duration
+    	just a timeout (default 10s)
+  -v	verbose output
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.SetMode(GNUMode)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Bool(new(bool), "v", "", false, "verbose output")
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+}
+
+func TestFlagSet_Interspersed(t *testing.T) {
+	testCases := []struct {
+		mode    ParseMode
+		args    []string
+		verbose bool
+		name    string
+		rest    []string
+	}{
+		{StdlibMode, []string{"file1", "-v", "file2"}, true, "", []string{"file1", "file2"}},
+		{StdlibMode, []string{"a", "--n", "x", "b", "-v=false"}, false, "x", []string{"a", "b"}},
+		{StdlibMode, []string{"a", "-name=x", "--", "-v", "b"}, false, "x", []string{"a", "-v", "b"}},
+		{StdlibMode, []string{"-", "-v"}, true, "", []string{"-"}},
+		{GNUMode, []string{"a", "-vnx", "b", "--name", "y", "c"}, true, "y", []string{"a", "b", "c"}},
+		{GNUMode, []string{"a", "--", "-v"}, false, "", []string{"a", "-v"}},
+	}
+
+	for _, tc := range testCases {
+		var verbose bool
+		var name string
+		fset := NewFlagSet("testing", io.Discard)
+		fset.SetMode(tc.mode)
+		fset.SetInterspersed(true)
+		fset.Bool(&verbose, "verbose", "v", false, "verbose output")
+		fset.String(&name, "name", "n", "", "just a name")
+
+		err := fset.Parse(tc.args)
+		failIfErr(t, err)
+
+		mustEqual(t, verbose, tc.verbose)
+		mustEqual(t, name, tc.name)
+		mustEqual(t, fset.Args(), tc.rest)
+		mustEqual(t, fset.IsParsed(), true)
+	}
+}
+
+func TestFlagSet_InterspersedBad(t *testing.T) {
+	fset := NewFlagSet("testing", io.Discard)
+	fset.SetInterspersed(true)
+	fset.Int(new(int), "count", "c", 0, "just a count")
+
+	err := fset.Parse([]string{"a", "-c", "x"})
+	mustEqual(t, err.Error(), `invalid value "x" for flag -c: parse error`)
+
+	err = fset.Parse([]string{"a", "-d"})
+	mustEqual(t, err.Error(), "flag provided but not defined: -d")
+}
+
+func TestFlagSet_Usage(t *testing.T) {
+	const usage = `Usage of testing:
+  -timeout (-t) duration
+    	just a timeout (default 10s)
+`
+	testCases := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-h"}, flag.ErrHelp.Error()},
+		{[]string{"-t", "abc"}, `invalid value "abc" for flag -t: parse error`},
+		{[]string{"-unknown"}, "flag provided but not defined: -unknown"},
+	}
+
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		fset := NewFlagSet("testing", &buf)
+		fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+
+		err := fset.Parse(tc.args)
+		mustEqual(t, err.Error(), tc.err)
+		want := usage
+		if err != flag.ErrHelp {
+			want = tc.err + "\n" + usage
+		}
+		mustEqual(t, buf.String(), want)
+	}
+
+	var called bool
+	fset := NewFlagSet("testing", io.Discard)
+	fset.SetUsage(func() { called = true })
+	err := fset.Parse([]string{"-h"})
+	mustEqual(t, err, flag.ErrHelp)
+	mustEqual(t, called, true)
+}
+
+func TestFlagSet_ErrorHandling(t *testing.T) {
+	fset := NewFlagSet("testing", io.Discard)
+	fset.Int(new(int), "count", "c", 0, "just a count")
+	mustEqual(t, fset.ErrorHandling(), flag.ContinueOnError)
+
+	fset.SetErrorHandling(flag.PanicOnError)
+	mustEqual(t, fset.ErrorHandling(), flag.PanicOnError)
+
+	defer func() {
+		err, ok := recover().(error)
+		mustEqual(t, ok, true)
+		mustEqual(t, err.Error(), `invalid value "x" for flag -c: parse error`)
+	}()
+	_ = fset.Parse([]string{"-c", "x"})
+	t.Fatal("must panic")
+}
+
+func TestFlagSet_UsageOnErrors(t *testing.T) {
+	const usage = `Usage of testing:
+  -count (-c) int
+    	just a count (required)
+`
+	testCases := []struct {
+		env  string
+		args []string
+		err  string
+	}{
+		{"", nil, "missing required flag: -count (-c)"},
+		{"x", nil, `invalid value "x" for env TESTING_COUNT: parse error`},
+	}
+
+	for _, tc := range testCases {
+		t.Setenv("TESTING_COUNT", tc.env)
+
+		var buf bytes.Buffer
+		fset := NewFlagSet("testing", &buf)
+		fset.Int(new(int), "count", "c", 0, "just a count")
+		fset.Required("count")
+		fset.UseEnv()
+
+		err := fset.Parse(tc.args)
+		mustEqual(t, err.Error(), tc.err)
+		mustEqual(t, buf.String(), tc.err+"\n"+usage)
+	}
+}
+
+func TestFlagSet_ExitOnError(t *testing.T) {
+	if os.Getenv("FLAGX_TEST_EXIT") == "1" {
+		fset := NewFlagSet("testing", os.Stderr)
+		fset.Int(new(int), "count", "c", 0, "just a count")
+		fset.Required("count")
+		fset.SetErrorHandling(flag.ExitOnError)
+		_ = fset.Parse(nil)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFlagSet_ExitOnError$")
+	cmd.Env = append(os.Environ(), "FLAGX_TEST_EXIT=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	mustEqual(t, errors.As(err, &exitErr), true)
+	mustEqual(t, exitErr.ExitCode(), 2)
+	mustEqual(t, strings.HasPrefix(stderr.String(), "missing required flag: -count (-c)\nUsage of testing:\n"), true)
+}
@@ -11,8 +11,12 @@ type SetOfInt map[int]struct{}
 
 // Set is flag.Value.Set
 func (si *SetOfInt) Set(v string) error {
+	return si.setList(strings.Split(v, ","))
+}
+
+func (si *SetOfInt) setList(strs []string) error {
 	ints := make(map[int]struct{})
-	for _, v := range strings.Split(v, ",") {
+	for _, v := range strs {
 		i, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return err
@@ -23,6 +27,11 @@ func (si *SetOfInt) Set(v string) error {
 	return nil
 }
 
+// Get is flag.Getter.Get
+func (si *SetOfInt) Get() interface{} {
+	return map[int]struct{}(*si)
+}
+
 func (si *SetOfInt) String() string {
 	if si == nil {
 		return ""
@@ -39,8 +48,12 @@ type SetOfInt64 map[int64]struct{}
 
 // Set is flag.Value.Set
 func (si *SetOfInt64) Set(v string) error {
+	return si.setList(strings.Split(v, ","))
+}
+
+func (si *SetOfInt64) setList(strs []string) error {
 	ints := make(map[int64]struct{})
-	for _, v := range strings.Split(v, ",") {
+	for _, v := range strs {
 		i, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return err
@@ -51,6 +64,11 @@ func (si *SetOfInt64) Set(v string) error {
 	return nil
 }
 
+// Get is flag.Getter.Get
+func (si *SetOfInt64) Get() interface{} {
+	return map[int64]struct{}(*si)
+}
+
 func (si *SetOfInt64) String() string {
 	if si == nil {
 		return ""
@@ -67,8 +85,12 @@ type SetOfUint map[uint]struct{}
 
 // Set is flag.Value.Set
 func (su *SetOfUint) Set(v string) error {
+	return su.setList(strings.Split(v, ","))
+}
+
+func (su *SetOfUint) setList(strs []string) error {
 	ints := make(map[uint]struct{})
-	for _, v := range strings.Split(v, ",") {
+	for _, v := range strs {
 		i, err := strconv.ParseUint(v, 10, 64)
 		if err != nil {
 			return err
@@ -79,6 +101,11 @@ func (su *SetOfUint) Set(v string) error {
 	return nil
 }
 
+// Get is flag.Getter.Get
+func (su *SetOfUint) Get() interface{} {
+	return map[uint]struct{}(*su)
+}
+
 func (su *SetOfUint) String() string {
 	if su == nil {
 		return ""
@@ -95,8 +122,12 @@ type SetOfUint64 map[uint64]struct{}
 
 // Set is flag.Value.Set
 func (su *SetOfUint64) Set(v string) error {
+	return su.setList(strings.Split(v, ","))
+}
+
+func (su *SetOfUint64) setList(strs []string) error {
 	ints := make(map[uint64]struct{})
-	for _, v := range strings.Split(v, ",") {
+	for _, v := range strs {
 		i, err := strconv.ParseUint(v, 10, 64)
 		if err != nil {
 			return err
@@ -107,6 +138,11 @@ func (su *SetOfUint64) Set(v string) error {
 	return nil
 }
 
+// Get is flag.Getter.Get
+func (su *SetOfUint64) Get() interface{} {
+	return map[uint64]struct{}(*su)
+}
+
 func (su *SetOfUint64) String() string {
 	if su == nil {
 		return ""
@@ -124,14 +160,23 @@ type SetOfString map[string]struct{}
 // Set is flag.Value.Set
 // TODO(cristaloleg): how to configure separator? , \t |
 func (ss *SetOfString) Set(v string) error {
-	strs := make(map[string]struct{})
-	for _, s := range strings.Split(v, ",") {
-		strs[s] = struct{}{}
+	return ss.setList(strings.Split(v, ","))
+}
+
+func (ss *SetOfString) setList(strs []string) error {
+	set := make(map[string]struct{})
+	for _, s := range strs {
+		set[s] = struct{}{}
 	}
-	*ss = SetOfString(strs)
+	*ss = SetOfString(set)
 	return nil
 }
 
+// Get is flag.Getter.Get
+func (ss *SetOfString) Get() interface{} {
+	return map[string]struct{}(*ss)
+}
+
 func (ss *SetOfString) String() string {
 	if ss == nil {
 		return ""
@@ -148,8 +193,12 @@ type SetOfFloat64 map[float64]struct{}
 
 // Set is flag.Value.Set
 func (sf *SetOfFloat64) Set(v string) error {
+	return sf.setList(strings.Split(v, ","))
+}
+
+func (sf *SetOfFloat64) setList(strs []string) error {
 	floats := make(map[float64]struct{})
-	for _, v := range strings.Split(v, ",") {
+	for _, v := range strs {
 		f, err := strconv.ParseFloat(v, 64)
 		if err != nil {
 			return err
@@ -160,6 +209,11 @@ func (sf *SetOfFloat64) Set(v string) error {
 	return nil
 }
 
+// Get is flag.Getter.Get
+func (sf *SetOfFloat64) Get() interface{} {
+	return map[float64]struct{}(*sf)
+}
+
 func (sf *SetOfFloat64) String() string {
 	if sf == nil {
 		return ""
@@ -176,8 +230,12 @@ type SetOfDuration map[time.Duration]struct{}
 
 // Set is flag.Value.Set
 func (sd *SetOfDuration) Set(v string) error {
+	return sd.setList(strings.Split(v, ","))
+}
+
+func (sd *SetOfDuration) setList(strs []string) error {
 	durs := make(map[time.Duration]struct{})
-	for _, v := range strings.Split(v, ",") {
+	for _, v := range strs {
 		d, err := time.ParseDuration(v)
 		if err != nil {
 			return err
@@ -188,6 +246,11 @@ func (sd *SetOfDuration) Set(v string) error {
 	return nil
 }
 
+// Get is flag.Getter.Get
+func (sd *SetOfDuration) Get() interface{} {
+	return map[time.Duration]struct{}(*sd)
+}
+
 func (sd *SetOfDuration) String() string {
 	if sd == nil {
 		return ""
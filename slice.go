@@ -7,6 +7,11 @@ import (
 	"time"
 )
 
+// listValue is implemented by the slice and set values to set all the elements at once.
+type listValue interface {
+	setList(strs []string) error
+}
+
 type boolSlice struct {
 	sep   string
 	value *[]bool
@@ -14,8 +19,12 @@ type boolSlice struct {
 
 // Set implements the flag.Value interface.
 func (s boolSlice) Set(str string) error {
+	return s.setList(strings.Split(str, s.sep))
+}
+
+func (s boolSlice) setList(strs []string) error {
 	var bools []bool
-	for _, v := range strings.Split(str, s.sep) {
+	for _, v := range strs {
 		b, err := strconv.ParseBool(v)
 		if err != nil {
 			return fmt.Errorf("parsing bool: %w", err)
@@ -26,8 +35,16 @@ func (s boolSlice) Set(str string) error {
 	return nil
 }
 
+// Get implements the flag.Getter interface.
+func (s boolSlice) Get() interface{} {
+	return *s.value
+}
+
 // String implements the flag.Value interface.
 func (s boolSlice) String() string {
+	if s.value == nil {
+		return ""
+	}
 	res := make([]string, len(*s.value))
 	for i, v := range *s.value {
 		res[i] = strconv.FormatBool(v)
@@ -42,8 +59,12 @@ type intSlice struct {
 
 // Set implements the flag.Value interface.
 func (s intSlice) Set(str string) error {
+	return s.setList(strings.Split(str, s.sep))
+}
+
+func (s intSlice) setList(strs []string) error {
 	var ints []int
-	for _, v := range strings.Split(str, s.sep) {
+	for _, v := range strs {
 		i, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return fmt.Errorf("parsing int: %w", err)
@@ -54,8 +75,16 @@ func (s intSlice) Set(str string) error {
 	return nil
 }
 
+// Get implements the flag.Getter interface.
+func (s intSlice) Get() interface{} {
+	return *s.value
+}
+
 // String implements the flag.Value interface.
 func (s intSlice) String() string {
+	if s.value == nil {
+		return ""
+	}
 	res := make([]string, len(*s.value))
 	for i, v := range *s.value {
 		res[i] = strconv.FormatInt(int64(v), 10)
@@ -70,8 +99,12 @@ type int64Slice struct {
 
 // Set implements the flag.Value interface.
 func (s int64Slice) Set(str string) error {
+	return s.setList(strings.Split(str, s.sep))
+}
+
+func (s int64Slice) setList(strs []string) error {
 	var ints []int64
-	for _, v := range strings.Split(str, s.sep) {
+	for _, v := range strs {
 		i, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
 			return fmt.Errorf("parsing int: %w", err)
@@ -82,8 +115,16 @@ func (s int64Slice) Set(str string) error {
 	return nil
 }
 
+// Get implements the flag.Getter interface.
+func (s int64Slice) Get() interface{} {
+	return *s.value
+}
+
 // String implements the flag.Value interface.
 func (s int64Slice) String() string {
+	if s.value == nil {
+		return ""
+	}
 	res := make([]string, len(*s.value))
 	for i, v := range *s.value {
 		res[i] = strconv.FormatInt(v, 10)
@@ -98,8 +139,12 @@ type uintSlice struct {
 
 // Set implements the flag.Value interface.
 func (s uintSlice) Set(str string) error {
+	return s.setList(strings.Split(str, s.sep))
+}
+
+func (s uintSlice) setList(strs []string) error {
 	var uints []uint
-	for _, v := range strings.Split(str, s.sep) {
+	for _, v := range strs {
 		i, err := strconv.ParseUint(v, 10, 64)
 		if err != nil {
 			return fmt.Errorf("parsing uint: %w", err)
@@ -110,8 +155,16 @@ func (s uintSlice) Set(str string) error {
 	return nil
 }
 
+// Get implements the flag.Getter interface.
+func (s uintSlice) Get() interface{} {
+	return *s.value
+}
+
 // String implements the flag.Value interface.
 func (s uintSlice) String() string {
+	if s.value == nil {
+		return ""
+	}
 	res := make([]string, len(*s.value))
 	for i, v := range *s.value {
 		res[i] = strconv.FormatUint(uint64(v), 10)
@@ -126,8 +179,12 @@ type uint64Slice struct {
 
 // Set implements the flag.Value interface.
 func (s uint64Slice) Set(str string) error {
+	return s.setList(strings.Split(str, s.sep))
+}
+
+func (s uint64Slice) setList(strs []string) error {
 	var uints []uint64
-	for _, v := range strings.Split(str, s.sep) {
+	for _, v := range strs {
 		i, err := strconv.ParseUint(v, 10, 64)
 		if err != nil {
 			return fmt.Errorf("parsing uint: %w", err)
@@ -138,8 +195,16 @@ func (s uint64Slice) Set(str string) error {
 	return nil
 }
 
+// Get implements the flag.Getter interface.
+func (s uint64Slice) Get() interface{} {
+	return *s.value
+}
+
 // String implements the flag.Value interface.
 func (s uint64Slice) String() string {
+	if s.value == nil {
+		return ""
+	}
 	res := make([]string, len(*s.value))
 	for i, v := range *s.value {
 		res[i] = strconv.FormatUint(v, 10)
@@ -154,12 +219,24 @@ type stringSlice struct {
 
 // Set implements the flag.Value interface.
 func (s stringSlice) Set(str string) error {
-	*s.value = strings.Split(str, s.sep)
+	return s.setList(strings.Split(str, s.sep))
+}
+
+func (s stringSlice) setList(strs []string) error {
+	*s.value = append([]string(nil), strs...)
 	return nil
 }
 
+// Get implements the flag.Getter interface.
+func (s stringSlice) Get() interface{} {
+	return *s.value
+}
+
 // String implements the flag.Value interface.
 func (s stringSlice) String() string {
+	if s.value == nil {
+		return ""
+	}
 	return strings.Join(*s.value, s.sep)
 }
 
@@ -170,8 +247,12 @@ type float64Slice struct {
 
 // Set implements the flag.Value interface.
 func (s float64Slice) Set(str string) error {
+	return s.setList(strings.Split(str, s.sep))
+}
+
+func (s float64Slice) setList(strs []string) error {
 	var floats []float64
-	for _, v := range strings.Split(str, s.sep) {
+	for _, v := range strs {
 		f, err := strconv.ParseFloat(v, 64)
 		if err != nil {
 			return fmt.Errorf("parsing float: %w", err)
@@ -182,8 +263,16 @@ func (s float64Slice) Set(str string) error {
 	return nil
 }
 
+// Get implements the flag.Getter interface.
+func (s float64Slice) Get() interface{} {
+	return *s.value
+}
+
 // String implements the flag.Value interface.
 func (s float64Slice) String() string {
+	if s.value == nil {
+		return ""
+	}
 	res := make([]string, len(*s.value))
 	for i, v := range *s.value {
 		res[i] = strconv.FormatFloat(v, 'g', 10, 64)
@@ -198,8 +287,12 @@ type durationSlice struct {
 
 // Set implements the flag.Value interface.
 func (s durationSlice) Set(str string) error {
+	return s.setList(strings.Split(str, s.sep))
+}
+
+func (s durationSlice) setList(strs []string) error {
 	var durs []time.Duration
-	for _, v := range strings.Split(str, s.sep) {
+	for _, v := range strs {
 		d, err := time.ParseDuration(v)
 		if err != nil {
 			return fmt.Errorf("parsing duration: %w", err)
@@ -210,8 +303,16 @@ func (s durationSlice) Set(str string) error {
 	return nil
 }
 
+// Get implements the flag.Getter interface.
+func (s durationSlice) Get() interface{} {
+	return *s.value
+}
+
 // String implements the flag.Value interface.
 func (s durationSlice) String() string {
+	if s.value == nil {
+		return ""
+	}
 	res := make([]string, len(*s.value))
 	for i, v := range *s.value {
 		res[i] = v.String()
@@ -166,3 +166,10 @@ func TestSliceDuration_Bad(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSliceZeroString(t *testing.T) {
+	mustEqual(t, boolSlice{}.String(), "")
+	mustEqual(t, intSlice{}.String(), "")
+	mustEqual(t, stringSlice{}.String(), "")
+	mustEqual(t, durationSlice{}.String(), "")
+}
@@ -0,0 +1,253 @@
+package flagx
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+)
+
+// Spec is a machine-readable description of a FlagSet, see FlagSet.Spec.
+type Spec struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Flags       []FlagSpec       `json:"flags"`
+	Arguments   []FlagSpec       `json:"arguments,omitempty"`
+	Constraints []ConstraintSpec `json:"constraints,omitempty"`
+}
+
+// FlagSpec describes a flag or a positional argument.
+type FlagSpec struct {
+	Name        string `json:"name"`
+	Alias       string `json:"alias,omitempty"`
+	Type        string `json:"type"`
+	Default     string `json:"default"`
+	Usage       string `json:"usage"`
+	Separator   string `json:"separator,omitempty"`
+	Env         string `json:"env,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Hidden      bool   `json:"hidden,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	Forward     bool   `json:"forward,omitempty"`
+}
+
+// ConstraintSpec describes a constraint between the flags,
+// kind is one of "at-most-one", "exactly-one", "all-or-none" and "requires".
+type ConstraintSpec struct {
+	Kind  string   `json:"kind"`
+	Flags []string `json:"flags"`
+}
+
+var constraintKinds = map[constraintKind]string{
+	atMostOne:  "at-most-one",
+	exactlyOne: "exactly-one",
+	allOrNone:  "all-or-none",
+	requires:   "requires",
+}
+
+// specTypes are the types which can be defined from a Spec with the value validation.
+var specTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, p := range []interface{}{
+		new(bool), new(int), new(int64), new(uint), new(uint64), new(string), new(float64), new(time.Duration),
+		new([]bool), new([]int), new([]int64), new([]uint), new([]uint64), new([]string), new([]float64), new([]time.Duration),
+		new(map[int]struct{}), new(map[int64]struct{}), new(map[uint]struct{}), new(map[uint64]struct{}),
+		new(map[string]struct{}), new(map[float64]struct{}), new(map[time.Duration]struct{}),
+	} {
+		t := reflect.TypeOf(p).Elem()
+		specTypes[t.String()] = t
+	}
+}
+
+// Spec returns the description of the flags, positional arguments and constraints.
+func (f *FlagSet) Spec() Spec {
+	spec := Spec{
+		Name:        f.fs.Name(),
+		Description: f.description,
+		Flags:       []FlagSpec{},
+	}
+	f.visitFlags(func(fl *flag.Flag) {
+		spec.Flags = append(spec.Flags, f.flagSpec(fl))
+	})
+	if p := f.positional; p != nil {
+		for _, name := range p.order {
+			spec.Arguments = append(spec.Arguments, p.flagSpec(p.fs.Lookup(name)))
+		}
+	}
+	for _, c := range f.constraints {
+		spec.Constraints = append(spec.Constraints, ConstraintSpec{
+			Kind:  constraintKinds[c.kind],
+			Flags: c.names,
+		})
+	}
+	return spec
+}
+
+func (f *FlagSet) flagSpec(fl *flag.Flag) FlagSpec {
+	fs := FlagSpec{
+		Name:      fl.Name,
+		Alias:     f.aliases[fl.Name],
+		Type:      flagType(fl),
+		Default:   fl.DefValue,
+		Usage:     fl.Usage,
+		Separator: separator(fl.Value),
+		Env:       f.EnvName(fl.Name),
+		Required:  f.required[fl.Name],
+		Group:     f.groupOf[fl.Name],
+		Hidden:    f.hidden[fl.Name],
+	}
+	if d, ok := f.deprecated[fl.Name]; ok {
+		fs.Deprecated, fs.Replacement, fs.Forward = true, d.replacement, d.forward
+	}
+	return fs
+}
+
+// WriteJSON writes the Spec of the FlagSet as JSON.
+func (f *FlagSet) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f.Spec())
+}
+
+// ReadJSON returns new FlagSet defined by the Spec read as JSON, see NewFlagSetFromSpec.
+func ReadJSON(r io.Reader, output io.Writer) (*FlagSet, error) {
+	var spec Spec
+	if err := json.NewDecoder(r).Decode(&spec); err != nil {
+		return nil, fmt.Errorf("flagx: decoding spec: %w", err)
+	}
+	return NewFlagSetFromSpec(spec, output)
+}
+
+// NewFlagSetFromSpec returns new FlagSet with the flags, positional arguments and constraints from spec.
+// Values of the basic types, their slices and sets are validated like in the original FlagSet,
+// values of other types are accepted as strings.
+func NewFlagSetFromSpec(spec Spec, output io.Writer) (*FlagSet, error) {
+	f := NewFlagSet(spec.Name, output)
+	f.description = spec.Description
+	for _, fs := range spec.Flags {
+		if err := f.defineSpec(fs); err != nil {
+			return nil, err
+		}
+	}
+	for _, fs := range spec.Flags {
+		if !fs.Deprecated {
+			continue
+		}
+		if _, ok := f.canonical(fs.Replacement); fs.Replacement != "" && !ok {
+			return nil, fmt.Errorf("flagx: replacement flag %s is not defined", fs.Replacement)
+		}
+		if fs.Forward && fs.Replacement == "" {
+			return nil, fmt.Errorf("flagx: deprecated flag %s cannot be forwarded without a replacement", fs.Name)
+		}
+		f.Deprecated(fs.Name, fs.Replacement, fs.Forward)
+	}
+	for _, fs := range spec.Arguments {
+		if err := f.Positional().defineSpec(fs); err != nil {
+			return nil, err
+		}
+	}
+	for _, cs := range spec.Constraints {
+		kind, ok := constraintKind(-1), false
+		for k, name := range constraintKinds {
+			if name == cs.Kind {
+				kind, ok = k, true
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("flagx: unknown constraint %q", cs.Kind)
+		}
+		for _, name := range cs.Flags {
+			if _, ok := f.canonical(name); !ok {
+				return nil, fmt.Errorf("flagx: constraint %s: flag %s is not defined", cs.Kind, name)
+			}
+		}
+		f.addConstraint(kind, cs.Flags)
+	}
+	return f, nil
+}
+
+func (f *FlagSet) defineSpec(fs FlagSpec) error {
+	if fs.Name == "" {
+		return fmt.Errorf("flagx: flag name must not be empty")
+	}
+	if f.Lookup(fs.Name) != nil || (fs.Alias != "" && f.Lookup(fs.Alias) != nil) {
+		return fmt.Errorf("flagx: flag %s redefined", fs.Name)
+	}
+	sep := fs.Separator
+	if sep == "" {
+		sep = ","
+	}
+
+	if t, ok := specTypes[fs.Type]; ok {
+		f.bindBasic(reflect.New(t).Interface(), fs.Name, fs.Alias, sep, fs.Usage)
+	} else {
+		f.Var(&specValue{typ: fs.Type}, fs.Name, fs.Alias, fs.Usage)
+	}
+	if fs.Default != "" {
+		if err := f.setDefault(fs.Name, fs.Default); err != nil {
+			return fmt.Errorf("flagx: %w", err)
+		}
+	}
+	if fs.Env != "" {
+		f.envs[fs.Name] = fs.Env
+	}
+	if fs.Required {
+		f.required[fs.Name] = true
+	}
+	if fs.Group != "" {
+		f.Group(fs.Group, fs.Name)
+	}
+	if fs.Hidden {
+		f.hidden[fs.Name] = true
+	}
+	return nil
+}
+
+// specValue holds the value of a flag of a type unknown to NewFlagSetFromSpec.
+type specValue struct {
+	typ   string
+	value string
+}
+
+func (v *specValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+func (v *specValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+// separator returns the separator of the slice and set values, empty for other values.
+func separator(v flag.Value) string {
+	switch v := v.(type) {
+	case boolSlice:
+		return v.sep
+	case intSlice:
+		return v.sep
+	case int64Slice:
+		return v.sep
+	case uintSlice:
+		return v.sep
+	case uint64Slice:
+		return v.sep
+	case stringSlice:
+		return v.sep
+	case float64Slice:
+		return v.sep
+	case durationSlice:
+		return v.sep
+	case listValue:
+		return ","
+	default:
+		return ""
+	}
+}
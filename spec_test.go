@@ -0,0 +1,135 @@
+package flagx
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_Spec(t *testing.T) {
+	const spec = `{
+  "name": "my-app",
+  "description": "just an app",
+  "flags": [
+    {
+      "name": "ids",
+      "type": "[]int",
+      "default": "1;2",
+      "usage": "just ids",
+      "separator": ";"
+    },
+    {
+      "name": "ip",
+      "type": "net.IP",
+      "default": "127.0.0.1",
+      "usage": "just an ip"
+    },
+    {
+      "name": "json",
+      "type": "bool",
+      "default": "false",
+      "usage": "json output"
+    },
+    {
+      "name": "tags",
+      "type": "map[string]struct {}",
+      "default": "",
+      "usage": "just tags",
+      "separator": ","
+    },
+    {
+      "name": "timeout",
+      "alias": "t",
+      "type": "time.Duration",
+      "default": "10s",
+      "usage": "just a timeout",
+      "env": "MY_APP_TIMEOUT",
+      "required": true
+    },
+    {
+      "name": "yaml",
+      "type": "bool",
+      "default": "false",
+      "usage": "yaml output"
+    }
+  ],
+  "arguments": [
+    {
+      "name": "src",
+      "type": "string",
+      "default": "",
+      "usage": "source",
+      "required": true
+    }
+  ],
+  "constraints": [
+    {
+      "kind": "at-most-one",
+      "flags": [
+        "json",
+        "yaml"
+      ]
+    }
+  ]
+}
+`
+	fset := NewFlagSet("my-app", io.Discard)
+	fset.SetDescription("just an app")
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.IntSlice(new([]int), "ids", "", []int{1, 2}, ";", "just ids")
+	fset.StringSet(new(map[string]struct{}), "tags", "", nil, "just tags")
+	fset.Text(new(net.IP), "ip", "", net.IPv4(127, 0, 0, 1), "just an ip")
+	fset.Bool(new(bool), "json", "", false, "json output")
+	fset.Bool(new(bool), "yaml", "", false, "yaml output")
+	fset.Required("timeout")
+	fset.Env("timeout", "MY_APP_TIMEOUT")
+	fset.MutuallyExclusive("json", "yaml")
+	fset.Positional().String(new(string), "src", "", "", "source")
+	fset.Positional().Required("src")
+
+	var buf bytes.Buffer
+	err := fset.WriteJSON(&buf)
+	failIfErr(t, err)
+	mustEqual(t, buf.String(), spec)
+
+	imported, err := ReadJSON(bytes.NewBufferString(spec), io.Discard)
+	failIfErr(t, err)
+
+	var buf2 bytes.Buffer
+	err = imported.WriteJSON(&buf2)
+	failIfErr(t, err)
+	mustEqual(t, buf2.String(), spec)
+
+	err = imported.Parse([]string{"-t", "1s", "-ids", "3;4", "-ip", "::1", "a"})
+	failIfErr(t, err)
+	mustEqual(t, imported.Lookup("ids").Value.String(), "3;4")
+
+	for _, args := range [][]string{
+		{"-t", "1x", "a"},
+		{"-ids", "x", "-t", "1s", "a"},
+		{"-t", "1s"},
+		{"-t", "1s", "-json", "-yaml", "a"},
+	} {
+		imported, err := ReadJSON(bytes.NewBufferString(spec), io.Discard)
+		failIfErr(t, err)
+		if err := imported.Parse(args); err == nil {
+			t.Fatalf("must fail for %v", args)
+		}
+	}
+}
+
+func TestNewFlagSetFromSpec_Bad(t *testing.T) {
+	for _, spec := range []Spec{
+		{Flags: []FlagSpec{{Name: ""}}},
+		{Flags: []FlagSpec{{Name: "a"}, {Name: "a"}}},
+		{Flags: []FlagSpec{{Name: "a", Type: "int", Default: "x"}}},
+		{Constraints: []ConstraintSpec{{Kind: "some-of"}}},
+		{Constraints: []ConstraintSpec{{Kind: "requires", Flags: []string{"a"}}}},
+	} {
+		if _, err := NewFlagSetFromSpec(spec, io.Discard); err == nil {
+			t.Fatalf("must fail for %+v", spec)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package flagx
+
+// detectsTerminal reports whether terminalWidth detects the terminals on this platform.
+const detectsTerminal = false
+
+// terminalWidth returns the number of columns of the terminal, false if fd is not a terminal.
+// The terminal is not detected on this platform, so only COLUMNS is used for the files.
+func terminalWidth(fd uintptr) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,22 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package flagx
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// detectsTerminal reports whether terminalWidth detects the terminals on this platform.
+const detectsTerminal = true
+
+// terminalWidth returns the number of columns of the terminal, false if fd is not a terminal.
+func terminalWidth(fd uintptr) (int, bool) {
+	var ws struct{ row, col, xpixel, ypixel uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, false
+	}
+	return int(ws.col), true
+}
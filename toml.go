@@ -0,0 +1,515 @@
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LoadTOML loads flag values from the TOML document read from r, like LoadJSON.
+// Must be called before Parse.
+//
+// Tables and dotted keys map to dotted names, so "host" in [db] sets -db.host,
+// inline tables map the same way. Arrays set slice and set flags, integers are
+// passed in decimal, other values are passed as written, strings unquoted.
+// Arrays of tables are not supported.
+func (f *FlagSet) LoadTOML(r io.Reader) error {
+	return f.loadConfig(r, "", parseTOML)
+}
+
+// parseTOML returns the values of the TOML document.
+func parseTOML(src []byte) ([]configValue, error) {
+	p := &tomlParser{
+		src:    string(src),
+		line:   1,
+		seen:   make(map[string]bool),
+		tables: make(map[string]bool),
+		dotted: make(map[string]bool),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.values, nil
+}
+
+// tomlParser parses a TOML document into config values.
+type tomlParser struct {
+	src    string
+	pos    int
+	line   int
+	table  string          // the current table with a trailing dot, empty for the root table.
+	seen   map[string]bool // the keys already defined.
+	tables map[string]bool // the tables already defined by a header.
+	dotted map[string]bool // the tables defined by dotted keys, like db in db.host = "x".
+	values []configValue
+}
+
+var tomlDateRegexp = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}([Tt ]\d{2}:\d{2}:\d{2}(\.\d+)?([Zz]|[+-]\d{2}:\d{2})?)?|\d{2}:\d{2}:\d{2}(\.\d+)?)$`)
+
+func (p *tomlParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("parsing TOML config: line %d: %s", p.line, fmt.Sprintf(format, a...))
+}
+
+func (p *tomlParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// unexpected returns the error for the current character.
+func (p *tomlParser) unexpected(expected string) error {
+	if p.eof() {
+		return p.errorf("unexpected end of file, expected %s", expected)
+	}
+	return p.errorf("unexpected %q, expected %s", p.peek(), expected)
+}
+
+// skipSpace skips the spaces and tabs.
+func (p *tomlParser) skipSpace() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.pos++
+	}
+}
+
+// skipComment skips the comment until the end of the line.
+func (p *tomlParser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+// skipNewline skips a newline and reports whether there was one.
+func (p *tomlParser) skipNewline() bool {
+	switch {
+	case p.peek() == '\n':
+		p.pos++
+	case strings.HasPrefix(p.src[p.pos:], "\r\n"):
+		p.pos += 2
+	default:
+		return false
+	}
+	p.line++
+	return true
+}
+
+// skipBlank skips the spaces, comments and newlines.
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		if !p.skipNewline() {
+			return
+		}
+	}
+}
+
+func (p *tomlParser) parse() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+		var err error
+		if p.peek() == '[' {
+			err = p.parseTable()
+		} else {
+			err = p.parseKeyValue(p.table)
+		}
+		if err != nil {
+			return err
+		}
+
+		p.skipSpace()
+		p.skipComment()
+		if !p.eof() && !p.skipNewline() {
+			return p.unexpected("end of line")
+		}
+	}
+}
+
+// parseTable parses a table header like [db].
+func (p *tomlParser) parseTable() error {
+	p.pos++ // [
+	if p.peek() == '[' {
+		return p.errorf("arrays of tables are not supported")
+	}
+	key, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if p.peek() != ']' {
+		return p.unexpected("]")
+	}
+	p.pos++
+	if p.tables[key] || p.seen[key] || p.dotted[key] {
+		return p.errorf("table %s defined twice", key)
+	}
+	if err := p.checkPrefixes("", key); err != nil {
+		return err
+	}
+	p.tables[key] = true
+	p.table = key + "."
+	return nil
+}
+
+// parseKey parses a dotted key and returns its parts joined with dots.
+func (p *tomlParser) parseKey() (string, error) {
+	var parts []string
+	for {
+		p.skipSpace()
+		var part string
+		switch p.peek() {
+		case '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return "", err
+			}
+			part = s
+		case '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return "", err
+			}
+			part = s
+		default:
+			start := p.pos
+			for isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return "", p.unexpected("a key")
+			}
+			part = p.src[start:p.pos]
+		}
+		parts = append(parts, part)
+
+		p.skipSpace()
+		if p.peek() != '.' {
+			return strings.Join(parts, "."), nil
+		}
+		p.pos++
+	}
+}
+
+// parseKeyValue parses a key/value pair, the key is prefixed with prefix.
+func (p *tomlParser) parseKeyValue(prefix string) error {
+	key, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	key = prefix + key
+	if p.peek() != '=' {
+		return p.unexpected("=")
+	}
+	p.pos++
+	p.skipSpace()
+
+	switch {
+	case p.seen[key] || p.tables[key]:
+		return p.errorf("key %s defined twice", key)
+	case p.dotted[key]:
+		return p.errorf("key %s is both a value and a table", key)
+	}
+	if err := p.checkPrefixes(prefix, key); err != nil {
+		return err
+	}
+	for i := len(prefix); i < len(key); i++ {
+		if key[i] == '.' {
+			p.dotted[key[:i]] = true
+		}
+	}
+	p.seen[key] = true
+	line := p.line
+
+	switch p.peek() {
+	case '{':
+		return p.parseInlineTable(key + ".")
+	case '[':
+		list, err := p.parseArray(key)
+		if err != nil {
+			return err
+		}
+		p.values = append(p.values, configValue{key: key, values: list, list: true, line: line})
+	default:
+		s, err := p.parseScalar()
+		if err != nil {
+			return err
+		}
+		p.values = append(p.values, configValue{key: key, values: []string{s}, line: line})
+	}
+	return nil
+}
+
+// checkPrefixes returns an error if a prefix of the dotted key, longer than prefix, is a value.
+func (p *tomlParser) checkPrefixes(prefix, key string) error {
+	for i := len(prefix); i < len(key); i++ {
+		if key[i] == '.' && p.seen[key[:i]] {
+			return p.errorf("key %s is both a value and a table", key[:i])
+		}
+	}
+	return nil
+}
+
+// parseInlineTable parses an inline table like {host = "x", port = 1}.
+func (p *tomlParser) parseInlineTable(prefix string) error {
+	p.pos++ // {
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return nil
+	}
+	for {
+		if err := p.parseKeyValue(prefix); err != nil {
+			return err
+		}
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return nil
+		default:
+			return p.unexpected(", or }")
+		}
+	}
+}
+
+// parseArray parses an array of scalars, it may span several lines.
+func (p *tomlParser) parseArray(key string) ([]string, error) {
+	p.pos++ // [
+	list := []string{}
+	for {
+		p.skipBlank()
+		switch p.peek() {
+		case ']':
+			p.pos++
+			return list, nil
+		case '[', '{':
+			return nil, p.errorf("config key %s: arrays must contain only strings, numbers, booleans and dates", key)
+		}
+		s, err := p.parseScalar()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+
+		p.skipBlank()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return list, nil
+		default:
+			return nil, p.unexpected(", or ]")
+		}
+	}
+}
+
+// parseScalar parses a string, a number, a boolean or a date and returns it as a flag value.
+func (p *tomlParser) parseScalar() (string, error) {
+	switch rest := p.src[p.pos:]; {
+	case strings.HasPrefix(rest, `"""`):
+		return p.parseMultilineString(`"""`)
+	case strings.HasPrefix(rest, "'''"):
+		return p.parseMultilineString("'''")
+	case strings.HasPrefix(rest, `"`):
+		return p.parseBasicString()
+	case strings.HasPrefix(rest, "'"):
+		return p.parseLiteralString()
+	}
+
+	start := p.pos
+	for isBareValueChar(p.peek()) {
+		p.pos++
+	}
+	// The date and the time may be separated by a space.
+	if tok := p.src[start:p.pos]; len(tok) == 10 && tomlDateRegexp.MatchString(tok) &&
+		len(p.src) > p.pos+3 && p.src[p.pos] == ' ' && isDigit(p.src[p.pos+1]) && isDigit(p.src[p.pos+2]) && p.src[p.pos+3] == ':' {
+		p.pos++
+		for isBareValueChar(p.peek()) {
+			p.pos++
+		}
+	}
+
+	tok := p.src[start:p.pos]
+	switch tok {
+	case "":
+		return "", p.unexpected("a value")
+	case "true", "false":
+		return tok, nil
+	case "inf", "+inf":
+		return "+Inf", nil
+	case "-inf":
+		return "-Inf", nil
+	case "nan", "+nan", "-nan":
+		return "NaN", nil
+	}
+	if tomlDateRegexp.MatchString(tok) {
+		return tok, nil
+	}
+
+	digits := strings.ReplaceAll(tok, "_", "")
+	if len(digits) > 2 && digits[0] == '0' {
+		if base, ok := map[byte]int{'x': 16, 'o': 8, 'b': 2}[digits[1]]; ok {
+			n, err := strconv.ParseUint(digits[2:], base, 64)
+			if err != nil {
+				return "", p.errorf("invalid integer %s", tok)
+			}
+			return strconv.FormatUint(n, 10), nil
+		}
+	}
+	if unsigned := strings.TrimLeft(digits, "+-"); len(unsigned) > 1 && unsigned[0] == '0' && isDigit(unsigned[1]) {
+		return "", p.errorf("invalid value %s, leading zeros are not allowed", tok)
+	}
+	if n, err := strconv.ParseInt(digits, 10, 64); err == nil {
+		return strconv.FormatInt(n, 10), nil
+	}
+	if _, err := strconv.ParseFloat(digits, 64); err == nil {
+		return digits, nil
+	}
+	return "", p.errorf("invalid value %s", tok)
+}
+
+// parseBasicString parses a string in double quotes with escapes.
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++ // "
+	var b strings.Builder
+	for {
+		switch c := p.peek(); {
+		case p.eof() || c == '\n':
+			return "", p.errorf("unterminated string")
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseLiteralString parses a string in single quotes without escapes.
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++ // '
+	end := strings.IndexAny(p.src[p.pos:], "'\n")
+	if end < 0 || p.src[p.pos+end] == '\n' {
+		return "", p.errorf("unterminated string")
+	}
+	s := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+// parseMultilineString parses a multi-line string, delim is three double quotes for a basic
+// string with escapes or three single quotes for a literal string.
+// A newline right after the opening delimiter is trimmed.
+func (p *tomlParser) parseMultilineString(delim string) (string, error) {
+	p.pos += len(delim)
+	p.skipNewline()
+	var b strings.Builder
+	for {
+		if strings.HasPrefix(p.src[p.pos:], delim) {
+			// Up to two quotes are allowed right before the closing delimiter.
+			n := len(delim)
+			for n < len(delim)+2 && p.pos+n < len(p.src) && p.src[p.pos+n] == delim[0] {
+				n++
+			}
+			b.WriteString(p.src[p.pos+len(delim) : p.pos+n])
+			p.pos += n
+			return b.String(), nil
+		}
+
+		switch c := p.peek(); {
+		case p.eof():
+			return "", p.errorf("unterminated string")
+		case c == '\\' && delim == `"""`:
+			// A backslash at the end of a line trims the following whitespace and newlines.
+			i := p.pos + 1
+			for i < len(p.src) && (p.src[i] == ' ' || p.src[i] == '\t') {
+				i++
+			}
+			if i < len(p.src) && (p.src[i] == '\n' || p.src[i] == '\r') {
+				p.pos = i
+				for p.skipNewline() {
+					p.skipSpace()
+				}
+				continue
+			}
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		case p.skipNewline():
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseEscape parses an escape sequence in a basic string.
+func (p *tomlParser) parseEscape(b *strings.Builder) error {
+	p.pos++ // \
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return p.errorf("invalid escape sequence")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid escape sequence \\%c%s", c, p.src[p.pos:p.pos+n])
+		}
+		b.WriteRune(rune(code))
+		p.pos += n
+	default:
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+func isBareKeyChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '_' || c == '-'
+}
+
+func isBareValueChar(c byte) bool {
+	return isBareKeyChar(c) || c == '+' || c == '.' || c == ':'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
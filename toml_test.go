@@ -0,0 +1,110 @@
+package flagx
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_LoadTOML(t *testing.T) {
+	const config = `# flagx config
+name = "basic \"quoted\" \u00e9"
+path = 'C:\Users\flagx' # literal
+verbose = true
+tags = [
+	"a",
+	"b,c", # trailing comma
+]
+ids = [0x10, 1_000]
+started = 1979-05-27 07:32:00Z
+ratio = 1e-2
+
+[db]
+host = "db.local"
+"port" = 5_432
+limits = { timeout = "3s", retries = 0o7 }
+
+[server.tls]
+cert = """
+line one \
+  continued
+line two"""
+key = '''raw\n'''
+`
+
+	var name, path, started, host, cert, key string
+	var verbose bool
+	var tags []string
+	var ids []int
+	var ratio float64
+	var port, retries int
+	var timeout time.Duration
+	fset := NewFlagSet("testing", io.Discard)
+	fset.String(&name, "name", "", "", "just a name")
+	fset.String(&path, "path", "", "", "just a path")
+	fset.Bool(&verbose, "verbose", "v", false, "verbose output")
+	fset.StringSlice(&tags, "tags", "", nil, ",", "just tags")
+	fset.IntSlice(&ids, "ids", "", nil, ",", "just ids")
+	fset.String(&started, "started", "", "", "start time")
+	fset.Float64(&ratio, "ratio", "", 0, "just a ratio")
+	fset.String(&host, "db.host", "", "", "database host")
+	fset.Int(&port, "db.port", "", 0, "database port")
+	fset.Duration(&timeout, "db.limits.timeout", "", 0, "database timeout")
+	fset.Int(&retries, "db.limits.retries", "", 0, "database retries")
+	fset.String(&cert, "server.tls.cert", "", "", "certificate")
+	fset.String(&key, "server.tls.key", "", "", "key")
+
+	err := fset.LoadTOML(strings.NewReader(config))
+	failIfErr(t, err)
+	failIfErr(t, fset.Parse(nil))
+
+	mustEqual(t, name, `basic "quoted" é`)
+	mustEqual(t, path, `C:\Users\flagx`)
+	mustEqual(t, verbose, true)
+	mustEqual(t, tags, []string{"a", "b,c"})
+	mustEqual(t, ids, []int{16, 1000})
+	mustEqual(t, started, "1979-05-27 07:32:00Z")
+	mustEqual(t, ratio, 0.01)
+	mustEqual(t, host, "db.local")
+	mustEqual(t, port, 5432)
+	mustEqual(t, timeout, 3*time.Second)
+	mustEqual(t, retries, 7)
+	mustEqual(t, cert, "line one continued\nline two")
+	mustEqual(t, key, `raw\n`)
+}
+
+func TestFlagSet_LoadTOMLBad(t *testing.T) {
+	testCases := []struct {
+		config string
+		err    string
+	}{
+		{"name = \"x\nother = 1", "parsing TOML config: line 1: unterminated string"},
+		{"name = 'x' y", `parsing TOML config: line 1: unexpected 'y', expected end of line`},
+		{"\n\nname", "parsing TOML config: line 3: unexpected end of file, expected ="},
+		{"name = ", "parsing TOML config: line 1: unexpected end of file, expected a value"},
+		{"name = 1\nname = 2", "parsing TOML config: line 2: key name defined twice"},
+		{"[db]\n[db]", "parsing TOML config: line 2: table db defined twice"},
+		{"db = 1\ndb.host = 2", "parsing TOML config: line 2: key db is both a value and a table"},
+		{"db.host = 1\ndb = 2", "parsing TOML config: line 2: key db is both a value and a table"},
+		{"db.host = 1\n[db]", "parsing TOML config: line 2: table db defined twice"},
+		{"db = {host = 1}\n[db.pool]", "parsing TOML config: line 2: key db is both a value and a table"},
+		{"[db]\nhost = 1\n[db.host.x]", "parsing TOML config: line 3: key db.host is both a value and a table"},
+		{"name = 01", "parsing TOML config: line 1: invalid value 01, leading zeros are not allowed"},
+		{"name = -00.5", "parsing TOML config: line 1: invalid value -00.5, leading zeros are not allowed"},
+		{"[[db]]", "parsing TOML config: line 1: arrays of tables are not supported"},
+		{"tags = [[1]]", "parsing TOML config: line 1: config key tags: arrays must contain only strings, numbers, booleans and dates"},
+		{`name = "\q"`, `parsing TOML config: line 1: invalid escape sequence \q`},
+		{"name = 12abc", "parsing TOML config: line 1: invalid value 12abc"},
+		{"[other]\nname = 1", "unknown config key other.name at line 2"},
+	}
+
+	for _, tc := range testCases {
+		fset := NewFlagSet("testing", io.Discard)
+		fset.String(new(string), "name", "", "", "just a name")
+		fset.StringSlice(new([]string), "tags", "", nil, ",", "just tags")
+
+		err := fset.LoadTOML(strings.NewReader(tc.config))
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
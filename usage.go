@@ -0,0 +1,305 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Help is the data passed to the usage templates, see SetUsageTemplate.
+type Help struct {
+	Name        string
+	Synopsis    string // the positional arguments, like "src [files...]".
+	Description string
+	Groups      []HelpGroup // the flags without a group come first with an empty title.
+	Arguments   []HelpFlag
+	Constraints []string
+	Examples    []Example
+	Epilog      string
+}
+
+// HelpGroup is a group of flags, see Group.
+type HelpGroup struct {
+	Title string
+	Flags []HelpFlag
+}
+
+// HelpFlag describes a flag or a positional argument.
+type HelpFlag struct {
+	Name        string // the flag name with the dashes, like "-timeout".
+	Alias       string // the flag alias with the dashes, like "-t", empty means no alias.
+	Display     string // the name and the alias, like "-timeout (-t)".
+	Placeholder string // the value placeholder, see flag.UnquoteUsage.
+	Usage       string
+	Default     string // the default value, empty if it is the zero value for the flag type.
+	Type        string
+	Env         string
+	Required    bool
+	Deprecated  string // the deprecation note, like "deprecated, use -addr".
+}
+
+// defaultTemplates are the templates used by PrintDefaults ("defaults") and PrintUsage ("usage"),
+// "flag" renders a single HelpFlag.
+//
+// NOTE(junk1tm): "flag" follows flag.PrintDefaults with a few modifications to support aliases.
+const defaultTemplates = `{{define "flag"}}  {{color "flag" .Display}}{{with .Placeholder}} {{color "placeholder" .}}{{end}}` +
+	`{{if short .}}{{"\t"}}{{else}}{{"\n    \t"}}{{end}}{{wrap .Usage (notes .)}}
+{{end}}` +
+
+	`{{define "defaults"}}{{range .Groups}}{{if .Title}}
+{{color "heading" (print .Title ":")}}
+{{end}}{{range .Flags}}{{template "flag" .}}{{end}}{{end}}{{if .Arguments}}
+{{color "heading" "Arguments:"}}
+{{range .Arguments}}{{template "flag" .}}{{end}}{{end}}{{if .Constraints}}
+{{color "heading" "Constraints:"}}
+{{range .Constraints}}  {{.}}
+{{end}}{{end}}{{end}}` +
+
+	`{{define "usage"}}{{if .Synopsis}}Usage: {{.Name}} [flags] {{.Synopsis}}{{else if .Name}}Usage of {{.Name}}:{{else}}Usage:{{end}}
+{{with .Description}}{{.}}
+
+{{end}}{{template "defaults" .}}{{if .Examples}}
+{{color "heading" "Examples:"}}
+{{range .Examples}}  {{.Command}}
+    	{{wrap .Description}}
+{{end}}{{end}}{{with .Epilog}}
+{{.}}
+{{end}}{{end}}`
+
+// usageFuncs returns the template functions for the width, see SetWidth, and the colors, see SetColor.
+func usageFuncs(width int, colors bool) template.FuncMap {
+	color := func(style, s string) string {
+		if !colors {
+			return s
+		}
+		return colorize(style, s)
+	}
+	return template.FuncMap{
+		// indent indents the lines of a multiline usage as PrintDefaults does.
+		"indent": func(s string) string {
+			return strings.ReplaceAll(s, "\n", "\n    \t")
+		},
+		// wrap joins the strings, wraps the lines longer than the terminal width and indents them,
+		// see SetWidth. Usages are printed at the column 8.
+		"wrap": func(s ...string) string {
+			return wrapText(strings.Join(s, ""), width)
+		},
+		// color styles the string with ANSI escape codes if the colors are enabled, see SetColor.
+		"color": color,
+		// notes returns the default value, required and deprecation notes of the flag, like " (default 10s)".
+		"notes": func(fl HelpFlag) string {
+			var b strings.Builder
+			if fl.Default != "" {
+				b.WriteString(" " + color("default", "(default "+fl.Default+")"))
+			}
+			if fl.Required {
+				b.WriteString(" " + color("required", "(required)"))
+			}
+			if fl.Deprecated != "" {
+				b.WriteString(" " + color("deprecated", "("+fl.Deprecated+")"))
+			}
+			return b.String()
+		},
+		// short reports whether the flag usage is printed on the same line,
+		// like for the boolean flags of one ASCII letter.
+		"short": func(fl HelpFlag) bool {
+			n := len("  ") + len(fl.Display)
+			if fl.Placeholder != "" {
+				n += len(" ") + len(fl.Placeholder)
+			}
+			return n <= 4 // space, space, '-', 'x'.
+		},
+	}
+}
+
+var defaultUsageTemplate = template.Must(template.New("").Funcs(usageFuncs(0, false)).Parse(defaultTemplates))
+
+// SetUsageTemplate sets the template used by PrintDefaults and PrintUsage.
+// The text is parsed with text/template and receives Help, it may redefine any of
+// the templates "flag" (a single HelpFlag), "defaults" (used by PrintDefaults) and
+// "usage" (used by PrintUsage), others are kept as defaults.
+// The template function "indent" indents the lines of a usage string, "wrap" also
+// wraps them to the width, see SetWidth, and "color" styles a string, see SetColor.
+func (f *FlagSet) SetUsageTemplate(text string) error {
+	tmpl, err := template.Must(defaultUsageTemplate.Clone()).Parse(text)
+	if err != nil {
+		return fmt.Errorf("flagx: parsing usage template: %w", err)
+	}
+	f.usageTemplate = tmpl
+	return nil
+}
+
+// SetEpilog sets the text printed at the end of the usage, see PrintUsage.
+func (f *FlagSet) SetEpilog(epilog string) {
+	f.epilog = epilog
+}
+
+// SetWidth sets the width used to wrap the usage strings in PrintDefaults and PrintUsage.
+// 0 means the COLUMNS environment variable or the width of the terminal if the output is one,
+// otherwise no wrapping (the default), a negative width disables wrapping.
+// On the platforms where the terminal is not detected, like Windows, COLUMNS is used
+// for any file output.
+func (f *FlagSet) SetWidth(width int) {
+	f.width = width
+}
+
+// outputWidth returns the width used to wrap the usage strings, 0 means no wrapping.
+func (f *FlagSet) outputWidth() int {
+	switch {
+	case f.width > 0:
+		return f.width
+	case f.width < 0:
+		return 0
+	}
+	file, ok := f.fs.Output().(*os.File)
+	if !ok {
+		return 0
+	}
+	// Without the terminal detection COLUMNS is trusted for any file.
+	width, ok := terminalWidth(file.Fd())
+	if !ok && detectsTerminal {
+		return 0
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return width
+}
+
+// wrapText wraps the lines of s longer than width at spaces, width includes the usage column,
+// and indents the following lines as PrintDefaults does. Width 0 means no wrapping.
+func wrapText(s string, width int) string {
+	const column = 8 // "    \t" or "  -x\t".
+	const minWidth = 20
+
+	if width > 0 {
+		width -= column
+		if width < minWidth {
+			width = minWidth
+		}
+	}
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		words := strings.Fields(line)
+		if width <= 0 || textWidth(line) <= width || len(words) == 0 {
+			lines = append(lines, line)
+			continue
+		}
+		cur := words[0]
+		for _, word := range words[1:] {
+			if textWidth(cur)+1+textWidth(word) > width {
+				lines = append(lines, cur)
+				cur = word
+			} else {
+				cur += " " + word
+			}
+		}
+		lines = append(lines, cur)
+	}
+	return strings.Join(lines, "\n    \t")
+}
+
+// PrintUsage prints, to standard error unless configured otherwise, the usage
+// with the program name, description, flags, examples and epilog.
+func (f *FlagSet) PrintUsage() {
+	f.printTemplate("usage")
+}
+
+// Help returns the data passed to the usage templates.
+func (f *FlagSet) Help() Help {
+	help, _ := f.help()
+	return help
+}
+
+// help returns the Help and the errors of calling String on the zero flag.Values.
+func (f *FlagSet) help() (Help, []error) {
+	var errs []error
+	help := Help{
+		Name:        f.fs.Name(),
+		Synopsis:    f.Synopsis(),
+		Description: f.description,
+		Examples:    f.examples,
+		Epilog:      f.epilog,
+	}
+
+	f.visitGroups(func(title string, flags []*flag.Flag) {
+		g := HelpGroup{Title: title}
+		for _, fl := range flags {
+			hf, err := f.helpFlag(fl)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			alias := f.aliases[fl.Name]
+			if alias != "" {
+				hf.Alias = f.dash(alias) + alias
+			}
+			hf.Name = f.dash(fl.Name) + fl.Name
+			hf.Display = f.flagName(fl.Name)
+			hf.Env = f.EnvName(fl.Name)
+			g.Flags = append(g.Flags, hf)
+		}
+		help.Groups = append(help.Groups, g)
+	})
+
+	if p := f.positional; p != nil {
+		for _, name := range p.order {
+			hf, err := p.helpFlag(p.fs.Lookup(name))
+			if err != nil {
+				errs = append(errs, err)
+			}
+			hf.Name, hf.Display = name, name
+			if p.isVariadic(name) {
+				hf.Display += "..."
+			}
+			help.Arguments = append(help.Arguments, hf)
+		}
+	}
+
+	for _, c := range f.constraints {
+		help.Constraints = append(help.Constraints, f.describe(c))
+	}
+	return help, errs
+}
+
+// helpFlag returns the HelpFlag without names.
+func (f *FlagSet) helpFlag(fl *flag.Flag) (HelpFlag, error) {
+	placeholder, usage := flag.UnquoteUsage(fl)
+	def, err := defaultValue(fl)
+	return HelpFlag{
+		Placeholder: placeholder,
+		Usage:       usage,
+		Default:     def,
+		Type:        flagType(fl),
+		Required:    f.required[fl.Name],
+		Deprecated:  f.deprecationNote(fl.Name),
+	}, err
+}
+
+// printTemplate executes the usage template with the given name to the FlagSet output.
+func (f *FlagSet) printTemplate(name string) {
+	tmpl := f.usageTemplate
+	if tmpl == nil {
+		tmpl = defaultUsageTemplate
+	}
+	if width, colors := f.outputWidth(), f.colors(); width > 0 || colors {
+		tmpl = template.Must(tmpl.Clone()).Funcs(usageFuncs(width, colors))
+	}
+	help, errs := f.help()
+
+	if err := tmpl.ExecuteTemplate(f.fs.Output(), name, help); err != nil {
+		errs = append(errs, err)
+	}
+
+	// If calling String on any zero flag.Values triggered a panic, print
+	// the messages after the full set of defaults so that the programmer
+	// knows to fix the panic.
+	if len(errs) > 0 {
+		fmt.Fprintln(f.fs.Output())
+		for _, err := range errs {
+			fmt.Fprintln(f.fs.Output(), err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package flagx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_PrintUsage(t *testing.T) {
+	const usage = `Usage: cp [flags] src [dst]
+Copy files.
+
+  -timeout (-t) duration
+    	just a timeout (default 10s)
+
+Arguments:
+  src string
+    	source file (required)
+  dst string
+    	destination file
+
+Examples:
+  cp a b
+    	copy a to b
+
+See the GUIDE for more.
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("cp", &buf)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Positional().String(new(string), "src", "", "", "source file")
+	fset.Positional().Required("src")
+	fset.Positional().String(new(string), "dst", "", "", "destination file")
+	fset.SetDescription("Copy files.")
+	fset.AddExample("cp a b", "copy a to b")
+	fset.SetEpilog("See the GUIDE for more.")
+
+	fset.PrintUsage()
+	mustEqual(t, buf.String(), usage)
+}
+
+func TestFlagSet_SetUsageTemplate(t *testing.T) {
+	const usage = `-timeout|-t|duration|10s|just a timeout
+-verbose||||verbose output
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Bool(new(bool), "verbose", "", false, "verbose output")
+
+	err := fset.SetUsageTemplate(`{{define "flag"}}{{.Name}}|{{.Alias}}|{{.Placeholder}}|{{.Default}}|{{.Usage}}
+{{end}}`)
+	failIfErr(t, err)
+
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+
+	err = fset.SetUsageTemplate(`{{define "flag"}}{{.Name}`)
+	mustEqual(t, err != nil, true)
+	mustEqual(t, strings.HasPrefix(err.Error(), "flagx: parsing usage template: "), true)
+}
+
+func TestFlagSet_Help(t *testing.T) {
+	fset := NewFlagSet("testing", nil)
+	fset.String(new(string), "addr", "a", "", "listen address")
+	fset.Int(new(int), "port", "", 80, "listen port")
+	fset.Group("Network", "port")
+	fset.Required("addr")
+
+	help := fset.Help()
+	mustEqual(t, help.Name, "testing")
+	mustEqual(t, len(help.Groups), 2)
+	mustEqual(t, help.Groups[0], HelpGroup{Flags: []HelpFlag{{
+		Name:        "-addr",
+		Alias:       "-a",
+		Display:     "-addr (-a)",
+		Placeholder: "string",
+		Usage:       "listen address",
+		Type:        "string",
+		Required:    true,
+	}}})
+	mustEqual(t, help.Groups[1].Title, "Network")
+	mustEqual(t, help.Groups[1].Flags[0].Default, "80")
+}
+
+func TestFlagSet_SetWidth(t *testing.T) {
+	const usage = `  -timeout (-t) duration
+    	the maximum time to wait for the
+    	server to respond before giving up
+    	(default 10s)
+  -v	print every request and response
+    	to the standard error
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second,
+		"the maximum time to wait for the server to respond before giving up")
+	fset.Bool(new(bool), "v", "", false, "print every request and response to the standard error")
+	fset.SetWidth(42)
+
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+
+	// COLUMNS is used only if the output is a terminal.
+	t.Setenv("COLUMNS", "42")
+	buf.Reset()
+	fset.SetWidth(0)
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), "  -timeout (-t) duration\n"+
+		"    \tthe maximum time to wait for the server to respond before giving up (default 10s)\n"+
+		"  -v\tprint every request and response to the standard error\n")
+
+	buf.Reset()
+	fset.SetWidth(-1)
+	fset.PrintDefaults()
+	mustEqual(t, strings.Count(buf.String(), "\n"), 3)
+}
@@ -0,0 +1,186 @@
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"strings"
+)
+
+// Required marks the flags with the specified names or aliases as required.
+// Parse returns an error naming every required flag that was set neither
+// on the command line, nor in the environment, nor in a loaded configuration file.
+// Required panics if a flag is not defined.
+func (f *FlagSet) Required(names ...string) {
+	for _, name := range names {
+		f.required[f.mustCanonical(name)] = true
+	}
+}
+
+// IsRequired reports whether the flag with the specified name or alias is required.
+func (f *FlagSet) IsRequired(name string) bool {
+	name, ok := f.canonical(name)
+	return ok && f.required[name]
+}
+
+// MutuallyExclusive declares that at most one of the flags with the specified names or aliases can be set.
+// MutuallyExclusive panics if a flag is not defined.
+func (f *FlagSet) MutuallyExclusive(names ...string) {
+	f.addConstraint(atMostOne, names)
+}
+
+// ExactlyOne declares that exactly one of the flags with the specified names or aliases must be set.
+// ExactlyOne panics if a flag is not defined.
+func (f *FlagSet) ExactlyOne(names ...string) {
+	f.addConstraint(exactlyOne, names)
+}
+
+// AllOrNone declares that the flags with the specified names or aliases must be set together or not at all.
+// AllOrNone panics if a flag is not defined.
+func (f *FlagSet) AllOrNone(names ...string) {
+	f.addConstraint(allOrNone, names)
+}
+
+// Requires declares that if the flag with the specified name is set then all the deps must be set too.
+// Requires panics if a flag is not defined.
+func (f *FlagSet) Requires(name string, deps ...string) {
+	f.addConstraint(requires, append([]string{name}, deps...))
+}
+
+type constraintKind int
+
+const (
+	atMostOne constraintKind = iota
+	exactlyOne
+	allOrNone
+	requires
+)
+
+// constraint between the flags, for requires the first name depends on the others.
+type constraint struct {
+	kind  constraintKind
+	names []string
+}
+
+func (f *FlagSet) addConstraint(kind constraintKind, names []string) {
+	c := constraint{kind: kind, names: make([]string, len(names))}
+	for i, name := range names {
+		c.names[i] = f.mustCanonical(name)
+	}
+	f.constraints = append(f.constraints, c)
+}
+
+func (f *FlagSet) hasConstraint(c constraint) bool {
+	for _, fc := range f.constraints {
+		if fc.kind == c.kind && strings.Join(fc.names, " ") == strings.Join(c.names, " ") {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns a description of the constraint violation, empty if the constraint holds.
+func (f *FlagSet) check(c constraint, actual map[string]bool) string {
+	var set int
+	for _, name := range c.names {
+		if actual[name] {
+			set++
+		}
+	}
+
+	switch c.kind {
+	case atMostOne:
+		if set > 1 {
+			return f.describe(c)
+		}
+	case exactlyOne:
+		if set != 1 {
+			return f.describe(c)
+		}
+	case allOrNone:
+		if set != 0 && set != len(c.names) {
+			return f.describe(c)
+		}
+	case requires:
+		if actual[c.names[0]] {
+			for _, name := range c.names[1:] {
+				if !actual[name] {
+					return f.describe(c)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// describe returns a description of the constraint as used by the errors and PrintDefaults.
+func (f *FlagSet) describe(c constraint) string {
+	names := make([]string, len(c.names))
+	for i, name := range c.names {
+		names[i] = f.flagName(name)
+	}
+
+	switch c.kind {
+	case atMostOne:
+		return "at most one of " + strings.Join(names, ", ") + " can be set"
+	case exactlyOne:
+		return "exactly one of " + strings.Join(names, ", ") + " must be set"
+	case allOrNone:
+		return "all or none of " + strings.Join(names, ", ") + " must be set"
+	case requires:
+		return names[0] + " requires " + strings.Join(names[1:], ", ")
+	default:
+		panic("unreachable")
+	}
+}
+
+// validate checks that all the required flags are set and all the constraints hold,
+// flags reported by skip and the constraints involving them are not checked.
+func (f *FlagSet) validate(skip func(name string) bool) error {
+	if skip == nil {
+		skip = func(string) bool { return false }
+	}
+	actual := f.actual()
+
+	var missing []string
+	f.VisitAll(func(fl *flag.Flag) {
+		if !f.required[fl.Name] || actual[fl.Name] || skip(fl.Name) {
+			return
+		}
+		missing = append(missing, f.flagName(fl.Name))
+	})
+
+	var errs []string
+	switch len(missing) {
+	case 0:
+	case 1:
+		errs = append(errs, "missing required flag: "+missing[0])
+	default:
+		errs = append(errs, "missing required flags: "+strings.Join(missing, ", "))
+	}
+
+constraints:
+	for _, c := range f.constraints {
+		for _, name := range c.names {
+			if skip(name) {
+				continue constraints
+			}
+		}
+		if msg := f.check(c, actual); msg != "" {
+			errs = append(errs, msg)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(errs, "; "))
+}
+
+// flagName returns the flag name with its alias as printed by PrintDefaults,
+// like "-timeout (-t)" or "--timeout (-t)" in GNUMode.
+func (f *FlagSet) flagName(name string) string {
+	if alias := f.aliases[name]; alias != "" {
+		return f.dash(name) + name + " (" + f.dash(alias) + alias + ")"
+	}
+	return f.dash(name) + name
+}
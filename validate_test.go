@@ -0,0 +1,136 @@
+package flagx
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_Required(t *testing.T) {
+	fset := NewFlagSet("testing", os.Stderr)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.String(new(string), "name", "", "", "just a name")
+	fset.Int(new(int), "count", "c", 0, "just a count")
+	fset.Required("t", "name", "count")
+
+	mustEqual(t, fset.IsRequired("timeout"), true)
+
+	err := fset.Parse([]string{"-c", "1"})
+	mustEqual(t, err.Error(), "missing required flags: -name, -timeout (-t)")
+}
+
+func TestFlagSet_RequiredEnv(t *testing.T) {
+	t.Setenv("TESTING_NAME", "env")
+
+	var name string
+	fset := NewFlagSet("testing", os.Stderr)
+	fset.String(&name, "name", "n", "", "just a name")
+	fset.Required("name")
+	fset.UseEnv()
+
+	err := fset.Parse(nil)
+	failIfErr(t, err)
+	mustEqual(t, name, "env")
+}
+
+func TestFlagSet_RequiredPrintDefaults(t *testing.T) {
+	const usage = `  -timeout (-t) duration
+    	just a timeout (default 10s) (required)
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.Duration(new(time.Duration), "timeout", "t", 10*time.Second, "just a timeout")
+	fset.Required("timeout")
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+}
+
+func TestCommand_Required(t *testing.T) {
+	var buf bytes.Buffer
+	var token string
+	newRoot := func() *Command {
+		root := NewCommand("app", "", &buf, nil)
+		root.PersistentFlags().String(&token, "token", "", "", "access token")
+		root.PersistentFlags().Required("token")
+		root.PersistentFlags().UseEnv()
+		root.AddCommand(NewCommand("run", "", &buf, func(args []string) error { return nil }))
+		return root
+	}
+
+	err := newRoot().Execute([]string{"run"})
+	mustEqual(t, err.Error(), "missing required flag: -token")
+
+	err = newRoot().Execute([]string{"-token", "cli", "run"})
+	failIfErr(t, err)
+	mustEqual(t, token, "cli")
+
+	t.Setenv("APP_TOKEN", "env")
+	err = newRoot().Execute([]string{"run"})
+	failIfErr(t, err)
+	mustEqual(t, token, "env")
+}
+
+func TestFlagSet_Constraints(t *testing.T) {
+	newFlagSet := func() *FlagSet {
+		fset := NewFlagSet("testing", os.Stderr)
+		fset.Bool(new(bool), "json", "j", false, "json output")
+		fset.Bool(new(bool), "yaml", "", false, "yaml output")
+		fset.String(new(string), "file", "f", "", "input file")
+		fset.String(new(string), "url", "", "", "input url")
+		fset.String(new(string), "user", "", "", "user name")
+		fset.String(new(string), "password", "", "", "user password")
+		fset.String(new(string), "cert", "", "", "tls cert")
+		fset.String(new(string), "key", "", "", "tls key")
+
+		fset.MutuallyExclusive("json", "yaml")
+		fset.ExactlyOne("f", "url")
+		fset.AllOrNone("user", "password")
+		fset.Requires("cert", "key")
+		return fset
+	}
+
+	testCases := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"-f", "x"}, ""},
+		{[]string{"-url", "x", "-json", "-user", "u", "-password", "p", "-cert", "c", "-key", "k"}, ""},
+		{[]string{"-f", "x", "-j", "-yaml"}, "at most one of -json (-j), -yaml can be set"},
+		{nil, "exactly one of -file (-f), -url must be set"},
+		{[]string{"-f", "x", "-url", "y"}, "exactly one of -file (-f), -url must be set"},
+		{[]string{"-f", "x", "-user", "u"}, "all or none of -user, -password must be set"},
+		{[]string{"-f", "x", "-cert", "c"}, "-cert requires -key"},
+		{[]string{"-json", "-yaml", "-cert", "c"}, "at most one of -json (-j), -yaml can be set; exactly one of -file (-f), -url must be set; -cert requires -key"},
+	}
+
+	for _, tc := range testCases {
+		err := newFlagSet().Parse(tc.args)
+		if tc.err == "" {
+			failIfErr(t, err)
+			continue
+		}
+		if err == nil {
+			t.Fatalf("must fail for %v", tc.args)
+		}
+		mustEqual(t, err.Error(), tc.err)
+	}
+}
+
+func TestFlagSet_ConstraintsPrintDefaults(t *testing.T) {
+	const usage = `  -json (-j)
+    	json output
+  -yaml
+    	yaml output
+
+Constraints:
+  at most one of -json (-j), -yaml can be set
+`
+	var buf bytes.Buffer
+	fset := NewFlagSet("testing", &buf)
+	fset.Bool(new(bool), "json", "j", false, "json output")
+	fset.Bool(new(bool), "yaml", "", false, "yaml output")
+	fset.MutuallyExclusive("json", "yaml")
+	fset.PrintDefaults()
+	mustEqual(t, buf.String(), usage)
+}
@@ -0,0 +1,460 @@
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LoadYAML loads flag values from the YAML document read from r, like LoadJSON.
+// Must be called before Parse.
+//
+// A pragmatic subset of YAML is supported: block mappings and sequences, flow
+// sequences and mappings on a single line, plain, quoted and block scalars
+// (| and >) and comments. Nested mappings map to dotted names, sequences set
+// slice and set flags, null values are ignored. Anchors, aliases, tags and
+// multiple documents are not supported.
+func (f *FlagSet) LoadYAML(r io.Reader) error {
+	return f.loadConfig(r, "", parseYAML)
+}
+
+// parseYAML returns the values of the YAML document.
+func parseYAML(src []byte) ([]configValue, error) {
+	p := &yamlParser{seen: make(map[string]bool)}
+	for i, text := range strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n") {
+		p.lines = append(p.lines, yamlLine{num: i + 1, text: text})
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.values, nil
+}
+
+// yamlParser parses a YAML document into config values, line by line.
+type yamlParser struct {
+	lines  []yamlLine
+	pos    int
+	seen   map[string]bool // the keys already defined.
+	values []configValue
+}
+
+// yamlLine is a line of a YAML document.
+type yamlLine struct {
+	num  int
+	text string
+}
+
+// indent returns the number of spaces before the content of the line.
+func (l yamlLine) indent() int {
+	return len(l.text) - len(strings.TrimLeft(l.text, " "))
+}
+
+// content returns the line without the indentation and the comment.
+func (l yamlLine) content() string {
+	return strings.TrimSpace(yamlStripComment(strings.TrimLeft(l.text, " ")))
+}
+
+func (p *yamlParser) errorf(l yamlLine, format string, a ...interface{}) error {
+	return fmt.Errorf("parsing YAML config: line %d: %s", l.num, fmt.Sprintf(format, a...))
+}
+
+// next skips the blank and comment lines and returns the next line.
+func (p *yamlParser) next() (yamlLine, bool) {
+	for ; p.pos < len(p.lines); p.pos++ {
+		if l := p.lines[p.pos]; l.content() != "" {
+			return l, true
+		}
+	}
+	return yamlLine{}, false
+}
+
+func (p *yamlParser) parse() error {
+	l, ok := p.next()
+	if ok && l.content() == "---" {
+		p.pos++
+		l, ok = p.next()
+	}
+	if !ok {
+		return nil
+	}
+	if isYAMLSequenceItem(l.content()) {
+		return p.errorf(l, "top-level value must be a mapping")
+	}
+	if err := p.parseMapping(l.indent(), ""); err != nil {
+		return err
+	}
+
+	if l, ok := p.next(); ok {
+		switch l.content() {
+		case "---":
+			return p.errorf(l, "multiple documents are not supported")
+		case "...":
+			return nil
+		}
+		return p.errorf(l, "unexpected indentation")
+	}
+	return nil
+}
+
+// parseMapping parses the block mapping at the indentation, the keys are prefixed with prefix.
+func (p *yamlParser) parseMapping(indent int, prefix string) error {
+	for {
+		l, ok := p.next()
+		if !ok || l.indent() < indent {
+			return nil
+		}
+		if strings.HasPrefix(l.text, "\t") || strings.HasPrefix(strings.TrimLeft(l.text, " "), "\t") {
+			return p.errorf(l, "tabs are not allowed in indentation")
+		}
+		content := l.content()
+		if content == "---" || content == "..." {
+			return nil
+		}
+		if l.indent() > indent {
+			return p.errorf(l, "unexpected indentation")
+		}
+		if isYAMLSequenceItem(content) {
+			return p.errorf(l, "unexpected sequence item")
+		}
+
+		key, value, err := p.splitKey(l, content)
+		if err != nil {
+			return err
+		}
+		key = prefix + key
+		if p.seen[key] {
+			return p.errorf(l, "key %s defined twice", key)
+		}
+		p.seen[key] = true
+		p.pos++
+
+		if err := p.parseValue(l, indent, key, value); err != nil {
+			return err
+		}
+	}
+}
+
+// parseValue parses the value of the key from the line l of the mapping at the indentation.
+func (p *yamlParser) parseValue(l yamlLine, indent int, key, value string) error {
+	switch {
+	case value == "":
+		next, ok := p.next()
+		switch {
+		case !ok || next.indent() < indent:
+			return nil // null.
+		case isYAMLSequenceItem(next.content()) && next.indent() >= indent:
+			return p.parseSequence(next.indent(), key)
+		case next.indent() > indent:
+			return p.parseMapping(next.indent(), key+".")
+		}
+		return nil // null.
+
+	case value[0] == '|' || value[0] == '>':
+		s, err := p.parseBlockScalar(l, indent, value)
+		if err != nil {
+			return err
+		}
+		p.values = append(p.values, configValue{key: key, values: []string{s}, line: l.num})
+
+	case value[0] == '[':
+		if !strings.HasSuffix(value, "]") {
+			return p.errorf(l, "flow sequences must be on a single line")
+		}
+		list := []string{}
+		for _, item := range yamlSplitFlow(value[1 : len(value)-1]) {
+			if item == "" {
+				continue
+			}
+			if item[0] == '[' || item[0] == '{' {
+				return p.errorf(l, "config key %s: sequences must contain only scalars", key)
+			}
+			s, _, err := p.parseScalar(l, item)
+			if err != nil {
+				return err
+			}
+			list = append(list, s)
+		}
+		p.values = append(p.values, configValue{key: key, values: list, list: true, line: l.num})
+
+	case value[0] == '{':
+		if !strings.HasSuffix(value, "}") {
+			return p.errorf(l, "flow mappings must be on a single line")
+		}
+		for _, item := range yamlSplitFlow(value[1 : len(value)-1]) {
+			if item == "" {
+				continue
+			}
+			k, v, err := p.splitKey(l, item)
+			if err != nil {
+				return err
+			}
+			k = key + "." + k
+			if p.seen[k] {
+				return p.errorf(l, "key %s defined twice", k)
+			}
+			p.seen[k] = true
+			if v != "" && strings.IndexByte("[{|>", v[0]) >= 0 {
+				return p.errorf(l, "config key %s: flow mappings must contain only scalars", key)
+			}
+			s, ok, err := p.parseScalar(l, v)
+			if err != nil {
+				return err
+			}
+			if ok {
+				p.values = append(p.values, configValue{key: k, values: []string{s}, line: l.num})
+			}
+		}
+
+	default:
+		s, ok, err := p.parseScalar(l, value)
+		if err != nil || !ok {
+			return err
+		}
+		p.values = append(p.values, configValue{key: key, values: []string{s}, line: l.num})
+	}
+	return nil
+}
+
+// parseSequence parses the block sequence of scalars at the indentation.
+func (p *yamlParser) parseSequence(indent int, key string) error {
+	first, _ := p.next()
+	list := []string{}
+	for {
+		l, ok := p.next()
+		if !ok || l.indent() != indent || !isYAMLSequenceItem(l.content()) {
+			if ok && l.indent() > indent {
+				return p.errorf(l, "unexpected indentation")
+			}
+			p.values = append(p.values, configValue{key: key, values: list, list: true, line: first.num})
+			return nil
+		}
+		p.pos++
+
+		item := strings.TrimSpace(strings.TrimPrefix(l.content(), "-"))
+		if item == "" || item[0] == '[' || item[0] == '{' || item[0] == '-' || item[0] == '|' || item[0] == '>' {
+			return p.errorf(l, "config key %s: sequences must contain only scalars", key)
+		}
+		if _, _, err := p.splitKey(l, item); err == nil {
+			return p.errorf(l, "config key %s: sequences must contain only scalars", key)
+		}
+		s, _, err := p.parseScalar(l, item)
+		if err != nil {
+			return err
+		}
+		list = append(list, s)
+	}
+}
+
+// parseBlockScalar parses the literal (|) or folded (>) block scalar following the line l
+// of the mapping at the indentation, header is the indicator with the optional chomping.
+func (p *yamlParser) parseBlockScalar(l yamlLine, indent int, header string) (string, error) {
+	chomping := header[1:]
+	if chomping != "" && chomping != "-" && chomping != "+" {
+		return "", p.errorf(l, "unsupported block scalar header %s", header)
+	}
+
+	var lines []string
+	blockIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		text := p.lines[p.pos].text
+		if strings.TrimSpace(text) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		n := p.lines[p.pos].indent()
+		if blockIndent < 0 {
+			if n <= indent {
+				break
+			}
+			blockIndent = n
+		}
+		if n < blockIndent {
+			break
+		}
+		lines = append(lines, text[blockIndent:])
+	}
+
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		switch {
+		case i == 0:
+		case header[0] == '|', line == "":
+			b.WriteByte('\n')
+		case lines[i-1] != "":
+			b.WriteByte(' ')
+		}
+		b.WriteString(line)
+	}
+	if len(lines) > 0 {
+		switch chomping {
+		case "":
+			b.WriteByte('\n')
+		case "+":
+			b.WriteString(strings.Repeat("\n", trailing+1))
+		}
+	}
+	return b.String(), nil
+}
+
+// parseScalar returns the value of the flow scalar, false for the null values.
+func (p *yamlParser) parseScalar(l yamlLine, s string) (string, bool, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		v, ok := yamlUnquote(s)
+		if !ok {
+			return "", false, p.errorf(l, "invalid double-quoted string %s", s)
+		}
+		return v, true, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", false, p.errorf(l, "invalid single-quoted string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), true, nil
+	case strings.HasPrefix(s, "&"), strings.HasPrefix(s, "*"), strings.HasPrefix(s, "!"):
+		return "", false, p.errorf(l, "anchors, aliases and tags are not supported")
+	}
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return "", false, nil
+	}
+	return s, true, nil
+}
+
+// yamlEscapes are the single character escape sequences of the double-quoted scalars.
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v", 'f': "\f", 'r': "\r",
+	'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// yamlUnquote returns the value of the double-quoted scalar s with the YAML escape sequences decoded.
+func yamlUnquote(s string) (string, bool) {
+	if len(s) < 2 || s[len(s)-1] != '"' {
+		return "", false
+	}
+	s = s[1 : len(s)-1]
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			return "", false
+		case c != '\\':
+			b.WriteByte(c)
+		case i+1 == len(s):
+			return "", false
+		default:
+			i++
+			if esc, ok := yamlEscapes[s[i]]; ok {
+				b.WriteString(esc)
+				continue
+			}
+			var n int
+			switch s[i] {
+			case 'x':
+				n = 2
+			case 'u':
+				n = 4
+			case 'U':
+				n = 8
+			}
+			if n == 0 || i+n >= len(s) {
+				return "", false
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", false
+			}
+			b.WriteRune(rune(code))
+			i += n
+		}
+	}
+	return b.String(), true
+}
+
+// splitKey splits the mapping entry into its key and value.
+func (p *yamlParser) splitKey(l yamlLine, s string) (key, value string, err error) {
+	if strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'") {
+		end := yamlQuoteEnd(s)
+		if end < 0 || !strings.HasPrefix(s[end:], ":") {
+			return "", "", p.errorf(l, "expected key: value")
+		}
+		key, _, err := p.parseScalar(l, s[:end])
+		return key, strings.TrimSpace(s[end+1:]), err
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == ':' && (i+1 == len(s) || s[i+1] == ' ') {
+			key := strings.TrimSpace(s[:i])
+			if key == "" {
+				break
+			}
+			return key, strings.TrimSpace(s[i+1:]), nil
+		}
+	}
+	return "", "", p.errorf(l, "expected key: value")
+}
+
+// isYAMLSequenceItem reports whether the line content is a block sequence item.
+func isYAMLSequenceItem(s string) bool {
+	return s == "-" || strings.HasPrefix(s, "- ")
+}
+
+// yamlQuoteEnd returns the index after the closing quote of the quoted scalar at the start of s, -1 if none.
+func yamlQuoteEnd(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// yamlStripComment removes the comment from the line, the quoted scalars are kept.
+func yamlStripComment(s string) string {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" ,[{:-", s[i-1]) >= 0):
+			end := yamlQuoteEnd(s[i:])
+			if end < 0 {
+				return s
+			}
+			i += end - 1
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// yamlSplitFlow splits the items of a flow collection at the commas outside quotes.
+func yamlSplitFlow(s string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case (c == '"' || c == '\'') && strings.TrimSpace(s[start:i]) == "":
+			if end := yamlQuoteEnd(s[i:]); end > 0 {
+				i += end - 1
+			}
+		case c == ',':
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, strings.TrimSpace(s[start:]))
+}
@@ -0,0 +1,149 @@
+package flagx
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSet_LoadYAML(t *testing.T) {
+	const config = `---
+# flagx config
+name: "quoted # not a comment\t"
+title: 'it''s' # a comment
+verbose: true
+empty: ~
+tags:
+- a
+- "b, c"
+ids: [1, 2, 3]
+db:
+  host: db.local
+  port: 5432
+  limits: {timeout: 3s, retries: 7}
+  hosts:
+    - one
+    - two
+motd: |
+  Hello,
+
+  world!
+summary: >-
+  folded
+  text
+
+  paragraph
+`
+
+	var name, title, empty, host, motd, summary string
+	var verbose bool
+	var tags, hosts []string
+	var ids []int
+	var port, retries int
+	var timeout time.Duration
+	fset := NewFlagSet("testing", io.Discard)
+	fset.String(&name, "name", "", "", "just a name")
+	fset.String(&title, "title", "", "", "just a title")
+	fset.String(&empty, "empty", "", "def", "just a default")
+	fset.Bool(&verbose, "verbose", "v", false, "verbose output")
+	fset.StringSlice(&tags, "tags", "", nil, ",", "just tags")
+	fset.IntSlice(&ids, "ids", "", nil, ",", "just ids")
+	fset.String(&host, "db.host", "", "", "database host")
+	fset.Int(&port, "db.port", "", 0, "database port")
+	fset.Duration(&timeout, "db.limits.timeout", "", 0, "database timeout")
+	fset.Int(&retries, "db.limits.retries", "", 0, "database retries")
+	fset.StringSlice(&hosts, "db.hosts", "", nil, ",", "database hosts")
+	fset.String(&motd, "motd", "", "", "message of the day")
+	fset.String(&summary, "summary", "", "", "just a summary")
+
+	err := fset.LoadYAML(strings.NewReader(config))
+	failIfErr(t, err)
+	failIfErr(t, fset.Parse(nil))
+
+	mustEqual(t, name, "quoted # not a comment\t")
+	mustEqual(t, title, "it's")
+	mustEqual(t, empty, "def")
+	mustEqual(t, verbose, true)
+	mustEqual(t, tags, []string{"a", "b, c"})
+	mustEqual(t, ids, []int{1, 2, 3})
+	mustEqual(t, host, "db.local")
+	mustEqual(t, port, 5432)
+	mustEqual(t, timeout, 3*time.Second)
+	mustEqual(t, retries, 7)
+	mustEqual(t, hosts, []string{"one", "two"})
+	mustEqual(t, motd, "Hello,\n\nworld!\n")
+	mustEqual(t, summary, "folded text\nparagraph")
+}
+
+func TestFlagSet_LoadYAMLPrecedence(t *testing.T) {
+	t.Setenv("TESTING_PORT", "8080")
+
+	var host string
+	var port int
+	fset := NewFlagSet("testing", io.Discard)
+	fset.String(&host, "host", "", "localhost", "just a host")
+	fset.Int(&port, "port", "", 80, "just a port")
+	fset.UseEnv()
+
+	err := fset.LoadYAML(strings.NewReader("host: file.local\nport: 443\n"))
+	failIfErr(t, err)
+	failIfErr(t, fset.Parse(nil))
+	mustEqual(t, host, "file.local")
+	mustEqual(t, port, 8080)
+}
+
+func TestYAMLUnquote(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want string
+	}{
+		{`"x\/y"`, "x/y"},
+		{`"\e[1m"`, "\x1b[1m"},
+		{`"a\ b\_c"`, "a b\u00a0c"},
+		{`"\N\L\P"`, "\u0085\u2028\u2029"},
+		{`"\x41\u00e9\U0001F600"`, "Aé😀"},
+		{`"\"\\\t\0"`, "\"\\\t\x00"},
+	}
+	for _, tc := range testCases {
+		have, ok := yamlUnquote(tc.s)
+		mustEqual(t, ok, true)
+		mustEqual(t, have, tc.want)
+	}
+}
+
+func TestFlagSet_LoadYAMLBad(t *testing.T) {
+	testCases := []struct {
+		config string
+		err    string
+	}{
+		{"- a", "parsing YAML config: line 1: top-level value must be a mapping"},
+		{"name: a\n  other: b", "parsing YAML config: line 2: unexpected indentation"},
+		{"name: a\nname: b", "parsing YAML config: line 2: key name defined twice"},
+		{"\n\njust text", "parsing YAML config: line 3: expected key: value"},
+		{"tags:\n  - a: b", "parsing YAML config: line 2: config key tags: sequences must contain only scalars"},
+		{"tags: [a, [b]]", "parsing YAML config: line 1: config key tags: sequences must contain only scalars"},
+		{"tags: [a,\n  b]", "parsing YAML config: line 1: flow sequences must be on a single line"},
+		{"name: *ref", "parsing YAML config: line 1: anchors, aliases and tags are not supported"},
+		{`name: "\q"`, `parsing YAML config: line 1: invalid double-quoted string "\q"`},
+		{`name: "\'"`, `parsing YAML config: line 1: invalid double-quoted string "\'"`},
+		{`name: "\x4"`, `parsing YAML config: line 1: invalid double-quoted string "\x4"`},
+		{"name: a\n---\nname: b", "parsing YAML config: line 2: multiple documents are not supported"},
+		{"\tname: a", "parsing YAML config: line 1: tabs are not allowed in indentation"},
+		{"name: a\nother: b", "unknown config key other at line 2"},
+		{"\ncount: x", `invalid value "x" for config key count at line 2: parse error`},
+	}
+
+	for _, tc := range testCases {
+		fset := NewFlagSet("testing", io.Discard)
+		fset.String(new(string), "name", "", "", "just a name")
+		fset.Int(new(int), "count", "", 0, "just a count")
+		fset.StringSlice(new([]string), "tags", "", nil, ",", "just tags")
+
+		err := fset.LoadYAML(strings.NewReader(tc.config))
+		if err == nil {
+			err = fset.Parse(nil)
+		}
+		mustEqual(t, err.Error(), tc.err)
+	}
+}